@@ -0,0 +1,712 @@
+package kalah
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPitWeights multiply the stones in the computer's pits 0-5
+// in the static value function. Stones closer to the store count more.
+// On boards with other than 6 pits, the weights go to the 6 pits
+// nearest the store, and any pits further away count 1.
+var DefaultPitWeights = [6]float64{2, 2, 2, 2, 3, 4}
+
+// AlphaBeta holds values that func chooseAlphaBeta() needs, but
+// aren't passed in as arguments.
+type AlphaBeta struct {
+	maxPly     int
+	PV         bool        // Principal Variation Search instead of plain alpha/beta
+	NullMove   bool        // null-move pruning, plain alpha/beta only
+	Futility   bool        // futility pruning near the horizon, plain alpha/beta only
+	MTDF       bool        // MTD(f) zero-window searches instead of alpha/beta's full window
+	Negamax    bool        // negamax formulation of plain alpha/beta, same results
+	PitWeights [6]float64  // static value weights of MAXIMIZER's 6 pits nearest the store
+	Book       OpeningBook // nil unless an opening book got loaded
+	Verbose    bool
+
+	// Positional, if not nil, adds mobility, bonus moves, capture
+	// threats, a far pit penalty and bonus move chains to the static value.
+	Positional *PositionalWeights
+
+	// TT, if not nil, is the transposition table plain alpha/beta and
+	// MTD(f) look positions up in, and keep from search to search.
+	TT *TranspositionTable
+
+	// NodesEvaluated is how many nodes below the root
+	// the last search reached, 0 for an opening book move.
+	NodesEvaluated int64
+
+	// history is the history heuristic table, indexed by
+	// playerIndex(player) and pit. Moves that caused a beta cutoff
+	// get a bigger score, and alphaBeta tries high scoring moves first.
+	// chooseAlphaBeta clears it at the start of every search.
+	history [2][MaxPits]int
+
+	// killers holds two killer moves per ply, non-capturing moves that
+	// caused a beta cutoff at that ply, most recent first. A value of -1
+	// means no killer. Only one player moves at any given ply, so there's
+	// no need to index by player. chooseAlphaBeta resets it every search.
+	killers [][2]int
+
+	exportTree *abTree // non-nil only after ExportGameTree()
+
+	line *pvTable // non-nil only after EnableBestLine(true)
+
+	trace *abTrace // non-nil only after TraceSearch()
+
+	// aspirationEnabled has chooseAlphaBeta deepen iteratively,
+	// searching each depth with a window of aspirationDelta either
+	// side of the previous depth's value.
+	aspirationEnabled bool
+	aspirationDelta   int
+	aspiration        aspirationStats // widening during the last search
+
+	// maxWindowSeen is the widest beta - alpha window that alphaBeta
+	// or pvSearch got called with during the last search, not counting
+	// windows with a bound still at -infinity or +infinity. Every search
+	// starts down its first line with a full window, so counting those
+	// would only ever show the root's window. If it's small, aspiration
+	// windows can be small too.
+	maxWindowSeen int
+
+	// Clock, if not nil, has chooseAlphaBeta deepen iteratively,
+	// up to the full depth, only as long as the clock's budget for
+	// the move allows. Whoever runs the game spends the time.
+	Clock *Clock
+
+	last abChoice // the last move chosen, for ExplainMove
+
+	rootValues map[int]int // if not nil, searchRoot puts every move's value in it
+}
+
+// DefaultAspirationDelta is how far either side of the previous
+// depth's value aspiration windows start out, unless set otherwise.
+const DefaultAspirationDelta = 50
+
+// aspirationStats counts how often aspiration windows had to widen
+// in one search, printed under Verbose.
+type aspirationStats struct {
+	searches int // calls to searchRoot, re-searches included
+	failLow  int // values at or below the window
+	failHigh int // values at or above it
+	maxDelta int // widest delta that got searched
+}
+
+// futilityMargin is how much better than its static value a position
+// one or two plies from the horizon could turn out, by some quiet move.
+// If even that doesn't reach the window, only captures and bonus moves
+// get searched.
+const futilityMargin = 50
+
+// nullMoveR is how many plies shallower than usual
+// the search after a null move goes.
+const nullMoveR = 2
+
+// NewAlphaBeta sets up alpha/beta minimaxing that looks
+// depth moves ahead for each side.
+func NewAlphaBeta(depth int) *AlphaBeta {
+	return &AlphaBeta{
+		maxPly:          2 * depth,
+		Futility:        true,
+		PitWeights:      DefaultPitWeights,
+		aspirationDelta: DefaultAspirationDelta,
+	}
+}
+
+// EnableAspiration turns aspiration windows on or off.
+// A delta of 0 or less keeps the current one, DefaultAspirationDelta
+// unless set otherwise.
+func (ab *AlphaBeta) EnableAspiration(enabled bool, delta int) {
+	ab.aspirationEnabled = enabled
+	if delta > 0 {
+		ab.aspirationDelta = delta
+	}
+}
+
+// ExportGameTree has every search write the first few plies of
+// its game tree to fileName as Graphviz DOT, leaving out nodes
+// with values at or below threshold.
+func (ab *AlphaBeta) ExportGameTree(fileName string, threshold int) {
+	ab.exportTree = &abTree{fileName: fileName, threshold: threshold}
+}
+
+// ChooseMove is a ChooserFunction
+func (ab *AlphaBeta) ChooseMove(ctx context.Context, bd Board, print bool) (bestpit int, bestvalue int, err error) {
+	return ab.chooseAlphaBeta(ctx, bd, print)
+}
+
+// chooseAlphaBeta looks for ctx's cancellation before searching each
+// of MAXIMIZER's moves at the root, except the first, so there's
+// always a move to give back with ErrSearchCancelled.
+func (ab *AlphaBeta) chooseAlphaBeta(ctx context.Context, bd Board, print bool) (bestpit int, bestvalue int, err error) {
+	if pit, found := ab.Book.lookup(bd); found {
+		if ab.Verbose {
+			fmt.Printf("Opening book move %d\n", pit)
+		}
+		ab.last = abChoice{board: bd, pit: pit, book: true, ok: true}
+		ab.NodesEvaluated = 0
+		ab.line.only(pit)
+		return pit, 0, nil
+	}
+	ab.newSearch()
+	if ab.MTDF {
+		// the last move's value is as good a guess as any
+		bestpit, bestvalue, err = ab.chooseMTDF(ctx, bd, ab.last.value, print)
+		ab.line.only(bestpit)
+	} else if ab.aspirationEnabled || ab.Clock != nil {
+		bestpit, bestvalue, err = ab.deepen(ctx, bd, ab.aspirationEnabled)
+	} else {
+		bestpit, bestvalue, err = ab.searchRoot(ctx, bd, 2*LOSS, 2*WIN)
+	}
+	if ab.Verbose {
+		fmt.Printf("max window: %d\n", ab.maxWindowSeen)
+		if ab.TT != nil {
+			fmt.Printf("transposition table: %d probes, %d hits, %d stores\n", ab.TT.Probes, ab.TT.Hits, ab.TT.Stores)
+		}
+	}
+	if ab.exportTree != nil && err != ErrNoMoves {
+		ab.exportTree.root.value = bestvalue
+		if err := ab.exportTree.writeDOT(); err != nil {
+			log.Print(err)
+		}
+	}
+	ab.last = abChoice{board: bd, pit: bestpit, value: bestvalue, ok: err != ErrNoMoves}
+	return bestpit, bestvalue, err
+}
+
+// newSearch clears what one search learns about move ordering,
+// so it doesn't carry over to the next, unrelated, position.
+func (ab *AlphaBeta) newSearch() {
+	ab.history = [2][MaxPits]int{}
+	if ab.TT != nil {
+		ab.TT.newSearch()
+	}
+	ab.maxWindowSeen = 0
+	ab.NodesEvaluated = 0
+	ab.line.newSearch()
+	ab.killers = make([][2]int, ab.maxPly+1)
+	for i := range ab.killers {
+		ab.killers[i] = [2]int{-1, -1}
+	}
+}
+
+// AspirationSearch chooses MAXIMIZER's move on bd by deepening
+// iteratively with aspiration windows delta either side of the
+// previous depth's value, whether or not EnableAspiration turned them
+// on for ChooseMove. A delta of 0 or less means DefaultAspirationDelta.
+// It doesn't look in the opening book.
+func (ab *AlphaBeta) AspirationSearch(bd Board, delta int) (bestpit int, bestvalue int) {
+	if delta <= 0 {
+		delta = DefaultAspirationDelta
+	}
+	saved := ab.aspirationDelta
+	defer func() { ab.aspirationDelta = saved }()
+	ab.aspirationDelta = delta
+	ab.newSearch()
+	bestpit, bestvalue, _ = ab.deepen(context.Background(), bd, true)
+	return bestpit, bestvalue
+}
+
+// MoveValues searches every one of MAXIMIZER's legal moves on bd,
+// the way ChooseMove does without iterative deepening or MTD(f), and
+// gives each one's value, by pit. It doesn't look in the opening book.
+// If ctx gets cancelled, it gives the moves it got to, and
+// ErrSearchCancelled.
+func (ab *AlphaBeta) MoveValues(ctx context.Context, bd Board) (map[int]int, error) {
+	values := make(map[int]int)
+	ab.rootValues = values
+	defer func() { ab.rootValues = nil }()
+	ab.newSearch()
+	_, _, err := ab.searchRoot(ctx, bd, 2*LOSS, 2*WIN)
+	return values, err
+}
+
+// searchRoot tries every one of MAXIMIZER's moves in bd, searching
+// each with the window alpha, beta. If ctx gets cancelled, it stops
+// after the move it's searching, with ErrSearchCancelled. If MAXIMIZER
+// has no moves, it's -1, with ErrNoMoves.
+func (ab *AlphaBeta) searchRoot(ctx context.Context, bd Board, alpha, beta int) (bestpit int, bestvalue int, err error) {
+	var buf [MaxPits]int
+	moves := bd.appendLegalMoves(buf[:0], MAXIMIZER)
+	if len(moves) == 0 {
+		return -1, 0, ErrNoMoves
+	}
+	search := ab.alphaBeta
+	if ab.PV {
+		search = ab.pvSearch
+	} else if ab.Negamax {
+		search = ab.negamaxAs
+	}
+	bestvalue = 2 * LOSS // -infinity
+	bestpit = 0
+	ab.exportTree.reset()
+	ab.trace.reset(ab.maxPly, alpha, beta)
+	h := ab.line.enter()
+	defer ab.line.leave()
+	var bd2 Board
+	for i, pit := range moves {
+		if i > 0 && ctx.Err() != nil {
+			return bestpit, bestvalue, ErrSearchCancelled
+		}
+		bd2 = bd.Clone()
+		ab.line.child(h)
+
+		node := ab.exportTree.enter(pit, MAXIMIZER)
+		ab.trace.enter(MAXIMIZER, pit, 0, alpha, beta)
+		next, _, err := MakeMove(&bd2, pit, MAXIMIZER)
+		if err != nil {
+			panic(err) // only legal moves, can't happen
+		}
+		var value int
+		if end, winner := CheckEnd(&bd2); end {
+			value = ab.terminalValue(winner, 0)
+		} else {
+			value = search(&bd2, 1, MINIMIZER, alpha, beta)
+		}
+		if next == MAXIMIZER {
+			// the search goes on as if MINIMIZER moved next, but
+			// after a bonus move it's a new search, not a line of play
+			ab.line.child(h)
+		}
+		ab.exportTree.leave(node, value, false)
+		ab.trace.leave(value)
+		if ab.rootValues != nil {
+			ab.rootValues[pit] = value
+		}
+		if value > bestvalue {
+			bestvalue = value
+			bestpit = pit
+			ab.line.update(h, pit)
+		}
+		// MakeMove() does a lot to bd2, just dump it.
+	}
+	ab.line.finish()
+	return bestpit, bestvalue, nil
+}
+
+// deepen deepens iteratively, 1 move for each side, then 2, up to the
+// full depth. The first depth gets the full window. If windowed, every
+// later depth starts with an aspiration window aspirationDelta either
+// side of the previous depth's value. A value outside the window means
+// the real value is somewhere else, so it re-searches with double the
+// delta. With a Clock, it doesn't start a depth that looks like it
+// would go past the clock's budget, guessing from how much longer
+// each depth took than the one before. If ctx gets cancelled, it
+// gives the last depth it finished, unless it's still on the first.
+func (ab *AlphaBeta) deepen(ctx context.Context, bd Board, windowed bool) (bestpit int, bestvalue int, err error) {
+	fullPly := ab.maxPly
+	defer func() { ab.maxPly = fullPly }()
+	ab.aspiration = aspirationStats{}
+	start := time.Now()
+	var budget, took, tookBefore time.Duration
+	if ab.Clock != nil {
+		budget = ab.Clock.Budget()
+	}
+
+	for ab.maxPly = 2; ; ab.maxPly += 2 {
+		first := ab.maxPly == 2
+		if ab.maxPly > fullPly {
+			ab.maxPly = fullPly
+		}
+		depthStart := time.Now()
+		donePit, doneValue := bestpit, bestvalue
+		if first || !windowed {
+			bestpit, bestvalue, err = ab.searchRoot(ctx, bd, 2*LOSS, 2*WIN)
+		} else {
+			previous := bestvalue
+			for delta := ab.aspirationDelta; ; delta *= 2 {
+				alpha, beta := previous-delta, previous+delta
+				if alpha < 2*LOSS {
+					alpha = 2 * LOSS
+				}
+				if beta > 2*WIN {
+					beta = 2 * WIN
+				}
+				bestpit, bestvalue, err = ab.searchRoot(ctx, bd, alpha, beta)
+				if err != nil {
+					break
+				}
+				ab.aspiration.searches++
+				if delta > ab.aspiration.maxDelta {
+					ab.aspiration.maxDelta = delta
+				}
+				if (bestvalue > alpha && bestvalue < beta) || (alpha == 2*LOSS && beta == 2*WIN) {
+					break
+				}
+				if bestvalue <= alpha {
+					ab.aspiration.failLow++
+				} else {
+					ab.aspiration.failHigh++
+				}
+				if ab.Verbose {
+					fmt.Printf("Aspiration window %d, %d failed at ply %d, value %d\n", alpha, beta, ab.maxPly, bestvalue)
+				}
+			}
+		}
+		if err != nil {
+			if !first {
+				bestpit, bestvalue = donePit, doneValue
+			}
+			return bestpit, bestvalue, err
+		}
+		tookBefore, took = took, time.Since(depthStart)
+		if ab.maxPly < fullPly && ab.Clock != nil {
+			growth := 4.0 // a guess, until there are two depths to go by
+			if tookBefore > 0 {
+				growth = float64(took) / float64(tookBefore)
+			}
+			if time.Since(start)+time.Duration(growth*float64(took)) > budget {
+				if ab.Verbose {
+					fmt.Printf("Out of time for the move after %d plies, %v\n", ab.maxPly, time.Since(start))
+				}
+				return bestpit, bestvalue, nil
+			}
+		}
+		if ab.maxPly >= fullPly {
+			if ab.Verbose && windowed {
+				s := ab.aspiration
+				fmt.Printf("Aspiration: %d windowed searches, %d failed low, %d failed high, widest delta %d\n",
+					s.searches, s.failLow, s.failHigh, s.maxDelta)
+			}
+			return bestpit, bestvalue, nil
+		}
+	}
+}
+
+// evaluate is the static value function: difference between pots less
+// ply depth, so that all things equal, choose the shortest path to a win,
+// plus some empirical amount of the seeds in computer's pits, and with
+// Positional, the positional parts of the value too.
+// alphaBeta and pvSearch only call it past the search horizon.
+func (ab *AlphaBeta) evaluate(bd *Board, ply int) int {
+	value := staticValue(bd, ply, &ab.PitWeights)
+	if ab.Positional != nil {
+		value += ab.Positional.positional(bd)
+	}
+	return value
+}
+
+// terminalValue is the value of a game that's over at ply, won
+// by winner, UNSET for a tie. Wins closer to the root are worth more,
+// and losses further away are less bad.
+func (ab *AlphaBeta) terminalValue(winner, ply int) int {
+	switch winner {
+	case MAXIMIZER:
+		return WIN - ply
+	case MINIMIZER:
+		return LOSS + ply
+	}
+	return 0 // end of game, but no winner
+}
+
+// ParsePitWeights turns a comma-separated list of 6 numbers,
+// like "2,2,2,2,3,4", into pit weights.
+func ParsePitWeights(str string) (weights [6]float64, err error) {
+	fields := strings.Split(str, ",")
+	if len(fields) != len(weights) {
+		return weights, fmt.Errorf("want %d pit weights, have %d in %q", len(weights), len(fields), str)
+	}
+	for i, f := range fields {
+		weights[i], err = strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return weights, fmt.Errorf("pit %d weight: %v", i, err)
+		}
+	}
+	return weights, nil
+}
+
+// alphaBeta does alpha-beta minimaxing. Computer is maximizer, human is minimizer.
+// Pass current game board (bd *Board) by reference to avoid having the compiler
+// create struct-copying code for each call to alphaBeta. With a TT, it looks
+// bd up before searching it, and stores what the search found.
+func (ab *AlphaBeta) alphaBeta(bd *Board, ply, player, alpha, beta int) int {
+	if ab.TT == nil || ab.maxPly-ply < ttMinDepth {
+		return ab.alphaBetaNode(bd, ply, player, alpha, beta)
+	}
+	key := bd.hashToMove(player)
+	depth := ab.maxPly - ply
+	if value, found := ab.TT.probe(key, depth, ply, alpha, beta); found {
+		return value
+	}
+	value := ab.alphaBetaNode(bd, ply, player, alpha, beta)
+	ab.TT.store(key, depth, ply, value, alpha, beta)
+	return value
+}
+
+// alphaBetaNode searches bd's moves for alphaBeta.
+func (ab *AlphaBeta) alphaBetaNode(bd *Board, ply, player, alpha, beta int) (value int) {
+	ab.enterNode(alpha, beta)
+	h := ab.line.enter()
+	defer ab.line.leave()
+	if ply > ab.maxPly {
+		return ab.evaluate(bd, ply)
+	}
+	// CheckEnd() should get the case where someone already has
+	// more than half the stones in their pot, so alphaBeta()
+	// only has to do depth check
+
+	var moves [MaxPits]int
+
+	switch player {
+	case MAXIMIZER:
+		if ab.nullMoveOK(bd, ply, beta) && ab.alphaBeta(bd, ply+nullMoveR+1, MINIMIZER, beta-1, beta) >= beta {
+			return beta
+		}
+		var bd2 Board
+		futile := ab.futile(bd, ply) && ab.evaluate(bd, ply)+futilityMargin <= alpha
+		n := ab.orderMoves(&bd.maxpits, bd.pits, MAXIMIZER, ply, &moves)
+		for _, pit := range moves[:n] {
+			if futile && ab.quiet(bd, MAXIMIZER, pit) {
+				continue
+			}
+			bd2 = bd.Clone()
+			ab.line.child(h)
+			node := ab.exportTree.enter(pit, player)
+			ab.trace.enter(player, pit, ply, alpha, beta)
+			nextplayer, plydelta, err := MakeMove(&bd2, pit, player)
+			if err != nil {
+				panic(err) // orderMoves only gives non-empty pits
+			}
+			if end, winner := CheckEnd(&bd2); end {
+				value = ab.terminalValue(winner, ply)
+			} else {
+				value = ab.alphaBeta(&bd2, ply+plydelta, nextplayer, alpha, beta)
+			}
+			if value > alpha {
+				alpha = value
+				ab.line.update(h, pit)
+			}
+			ab.exportTree.leave(node, value, beta <= alpha)
+			ab.trace.leave(value)
+			if beta <= alpha {
+				ab.recordCutoff(MAXIMIZER, pit, ply)
+				if !bd.captures(MAXIMIZER, pit) {
+					ab.recordKiller(pit, ply)
+				}
+				return value
+			}
+		}
+		// the best of the moves, or the bound
+		// the parent already has, if none beat it
+		return alpha
+	case MINIMIZER:
+		var bd2 Board
+		futile := ab.futile(bd, ply) && ab.evaluate(bd, ply)-futilityMargin >= beta
+		n := ab.orderMoves(&bd.minpits, bd.pits, MINIMIZER, ply, &moves)
+		for _, pit := range moves[:n] {
+			if futile && ab.quiet(bd, MINIMIZER, pit) {
+				continue
+			}
+			bd2 = bd.Clone()
+			ab.line.child(h)
+			node := ab.exportTree.enter(pit, player)
+			ab.trace.enter(player, pit, ply, alpha, beta)
+			nextplayer, plydelta, err := MakeMove(&bd2, pit, player)
+			if err != nil {
+				panic(err) // orderMoves only gives non-empty pits
+			}
+			if end, winner := CheckEnd(&bd2); end {
+				value = ab.terminalValue(winner, ply)
+			} else {
+				value = ab.alphaBeta(&bd2, ply+plydelta, nextplayer, alpha, beta)
+			}
+			if value < beta {
+				beta = value
+				ab.line.update(h, pit)
+			}
+			ab.exportTree.leave(node, value, beta <= alpha)
+			ab.trace.leave(value)
+			if beta <= alpha {
+				ab.recordCutoff(MINIMIZER, pit, ply)
+				if !bd.captures(MINIMIZER, pit) {
+					ab.recordKiller(pit, ply)
+				}
+				return value
+			}
+		}
+		return beta
+	}
+	return value
+}
+
+// futile reports whether futility pruning could apply at ply, one or
+// two plies from the horizon. Avalanches make captures and bonus moves
+// impossible to see coming, so it never applies with that rule.
+func (ab *AlphaBeta) futile(bd *Board, ply int) bool {
+	return ab.Futility && ply >= ab.maxPly-1 && !bd.rules.Avalanche
+}
+
+// quiet reports whether player's pit on bd neither captures nor earns
+// a bonus move, the moves that can't change the value much right before
+// the horizon.
+func (ab *AlphaBeta) quiet(bd *Board, player, pit int) bool {
+	return !bd.captures(player, pit) && !bd.earnsBonus(player, pit)
+}
+
+// nullMoveOK reports whether MAXIMIZER should try passing, letting
+// MINIMIZER move twice in a row, then searching what follows nullMoveR
+// plies shallower, with a null window at beta. If MAXIMIZER still gets
+// beta or better, an actual move would surely do at least as well, so
+// the node can be cut off without searching any. It doesn't try near
+// the root, where there's not enough depth left for a shallower search,
+// when beta is nearly a win already, or near the end of the game, when
+// passing is less like every other move.
+func (ab *AlphaBeta) nullMoveOK(bd *Board, ply, beta int) bool {
+	if !ab.NullMove || ply <= 2 || ply+nullMoveR+1 > ab.maxPly || beta >= WIN/2 {
+		return false
+	}
+	maxsidesum, minsidesum := bd.sideSums()
+	if maxsidesum < bd.pits || minsidesum < bd.pits {
+		return false
+	}
+	return true
+}
+
+// pvSearch does Principal Variation Search, taking the same arguments
+// as alphaBeta. It searches the first move, the best one if move ordering
+// did its job, with the full alpha/beta window. Remaining moves get searched
+// with a null window, which only shows whether they beat the first move.
+// Moves that do beat it get searched again with the full window.
+func (ab *AlphaBeta) pvSearch(bd *Board, ply, player, alpha, beta int) (value int) {
+	ab.enterNode(alpha, beta)
+	h := ab.line.enter()
+	defer ab.line.leave()
+	if ply > ab.maxPly {
+		return ab.evaluate(bd, ply)
+	}
+
+	var moves [MaxPits]int
+	var n int
+	var best int
+
+	switch player {
+	case MAXIMIZER:
+		n = ab.orderMoves(&bd.maxpits, bd.pits, MAXIMIZER, ply, &moves)
+		best = 2 * LOSS
+	case MINIMIZER:
+		n = ab.orderMoves(&bd.minpits, bd.pits, MINIMIZER, ply, &moves)
+		best = 2 * WIN
+	}
+
+	var bd2 Board
+	for i, pit := range moves[:n] {
+		bd2 = bd.Clone()
+		ab.line.child(h)
+		node := ab.exportTree.enter(pit, player)
+		ab.trace.enter(player, pit, ply, alpha, beta)
+		nextplayer, plydelta, err := MakeMove(&bd2, pit, player)
+		if err != nil {
+			panic(err) // orderMoves only gives non-empty pits
+		}
+		if end, winner := CheckEnd(&bd2); end {
+			value = ab.terminalValue(winner, ply)
+		} else if i == 0 {
+			value = ab.pvSearch(&bd2, ply+plydelta, nextplayer, alpha, beta)
+		} else if player == MAXIMIZER {
+			value = ab.pvSearch(&bd2, ply+plydelta, nextplayer, alpha, alpha+1)
+			if value > alpha && value < beta {
+				value = ab.pvSearch(&bd2, ply+plydelta, nextplayer, alpha, beta)
+			}
+		} else {
+			value = ab.pvSearch(&bd2, ply+plydelta, nextplayer, beta-1, beta)
+			if value < beta && value > alpha {
+				value = ab.pvSearch(&bd2, ply+plydelta, nextplayer, alpha, beta)
+			}
+		}
+
+		if player == MAXIMIZER {
+			if value > best {
+				best = value
+				ab.line.update(h, pit)
+			}
+			if value > alpha {
+				alpha = value
+			}
+		} else {
+			if value < best {
+				best = value
+				ab.line.update(h, pit)
+			}
+			if value < beta {
+				beta = value
+			}
+		}
+		ab.exportTree.leave(node, value, beta <= alpha)
+		ab.trace.leave(value)
+		if beta <= alpha {
+			ab.recordCutoff(player, pit, ply)
+			if !bd.captures(player, pit) {
+				ab.recordKiller(pit, ply)
+			}
+			break
+		}
+	}
+	return best
+}
+
+// enterNode counts a node the search has reached, and keeps
+// track of the widest finite alpha, beta window.
+func (ab *AlphaBeta) enterNode(alpha, beta int) {
+	ab.NodesEvaluated++
+	if alpha > 2*LOSS && beta < 2*WIN && beta-alpha > ab.maxWindowSeen {
+		ab.maxWindowSeen = beta - alpha
+	}
+}
+
+func playerIndex(player int) int {
+	if player == MAXIMIZER {
+		return 0
+	}
+	return 1
+}
+
+// recordCutoff credits pit with a beta cutoff at ply. Cutoffs
+// closer to the root prune more of the tree, so they count for more.
+func (ab *AlphaBeta) recordCutoff(player, pit, ply int) {
+	d := ab.maxPly - ply + 1
+	ab.history[playerIndex(player)][pit] += d * d
+}
+
+func (ab *AlphaBeta) recordKiller(pit, ply int) {
+	if ab.killers[ply][0] != pit {
+		ab.killers[ply][1] = ab.killers[ply][0]
+		ab.killers[ply][0] = pit
+	}
+}
+
+// orderMoves fills in moves with the non-empty pits of one side,
+// which has npits pits, killer moves for ply first, then best history
+// score first, and returns how many it filled in. Ties stay in pit order.
+func (ab *AlphaBeta) orderMoves(pits *[MaxPits + 1]int, npits int, player int, ply int, moves *[MaxPits]int) int {
+	scores := &ab.history[playerIndex(player)]
+	n := 0
+	for pit := 0; pit < npits; pit++ {
+		if pits[pit] == UNSET {
+			continue
+		}
+		// insertion sort, there's at most MaxPits moves
+		i := n
+		for ; i > 0 && scores[moves[i-1]] < scores[pit]; i-- {
+			moves[i] = moves[i-1]
+		}
+		moves[i] = pit
+		n++
+	}
+	// second killer, then first killer, to the front of the list,
+	// if they're legal moves in this position
+	for k := 1; k >= 0; k-- {
+		killer := ab.killers[ply][k]
+		for i := 0; i < n; i++ {
+			if moves[i] == killer {
+				copy(moves[1:i+1], moves[:i])
+				moves[0] = killer
+				break
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,135 @@
+package kalah
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// TestAlphaBetaNoMoves checks that every way alpha/beta searches gives
+// -1 and ErrNoMoves, as a ChooserFunction should, for a board where
+// MAXIMIZER has no stones left to play.
+func TestAlphaBetaNoMoves(t *testing.T) {
+	bd, err := BoardFromFEN("0.0.0.0.0.0/0.3.0.1.0.2 20 22 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setups := map[string]func(*AlphaBeta){
+		"plain":      func(ab *AlphaBeta) {},
+		"pv":         func(ab *AlphaBeta) { ab.PV = true },
+		"negamax":    func(ab *AlphaBeta) { ab.Negamax = true },
+		"mtdf":       func(ab *AlphaBeta) { ab.MTDF = true },
+		"aspiration": func(ab *AlphaBeta) { ab.EnableAspiration(true, 0) },
+	}
+	for name, setup := range setups {
+		ab := NewAlphaBeta(4)
+		setup(ab)
+		pit, value, err := ab.ChooseMove(context.Background(), bd, false)
+		if pit != -1 || value != 0 || err != ErrNoMoves {
+			t.Errorf("%s: got %d, %d, %v, want -1, 0, %v", name, pit, value, err, ErrNoMoves)
+		}
+		if explanation := ab.ExplainMove(bd); explanation != "" {
+			t.Errorf("%s: ExplainMove says %q, want nothing", name, explanation)
+		}
+	}
+	values, err := NewAlphaBeta(4).MoveValues(context.Background(), bd)
+	if len(values) != 0 || err != ErrNoMoves {
+		t.Errorf("MoveValues: got %v, %v, want none, %v", values, err, ErrNoMoves)
+	}
+}
+
+// TestFutilitySameMove checks that futility pruning, on by default,
+// doesn't change the move alpha/beta chooses: on the usual starting
+// positions, some positions where there's a capture, a bonus move or
+// the end of the game to find, and random positions from real games.
+func TestFutilitySameMove(t *testing.T) {
+	type position struct {
+		fen   string
+		depth int
+	}
+	positions := []position{
+		{NewBoard(4).FEN(), 5},
+		{NewBoard(3).FEN(), 5},
+		{NewBoardPits(4, 4).FEN(), 6},
+		{"4.4.4.4.2.1/5.5.5.5.4.4 1 0 1", 5},   // bonus moves from pits 4 and 5
+		{"1.0.6.6.0.6/6.6.1.0.6.0 5 5 1", 5},   // captures for both sides
+		{"0.0.1.0.2.1/1.0.0.0.3.0 20 20 1", 6}, // the end of the game
+	}
+	rng := rand.New(rand.NewSource(780))
+	for len(positions) < 100 {
+		if bd, ok := randomPosition(rng, 6, 4, rng.Intn(30)); ok {
+			positions = append(positions, position{bd.FEN(), 2 + rng.Intn(3)})
+		}
+	}
+	for _, pos := range positions {
+		bd, err := BoardFromFEN(pos.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		with := NewAlphaBeta(pos.depth)
+		withPit, _, err := with.ChooseMove(context.Background(), bd, false)
+		if err != nil {
+			t.Fatalf("%s: %v", pos.fen, err)
+		}
+		without := NewAlphaBeta(pos.depth)
+		without.Futility = false
+		withoutPit, _, err := without.ChooseMove(context.Background(), bd, false)
+		if err != nil {
+			t.Fatalf("%s: %v", pos.fen, err)
+		}
+		if withPit != withoutPit {
+			t.Errorf("%s depth %d: pit %d with futility pruning, %d without", pos.fen, pos.depth, withPit, withoutPit)
+		}
+	}
+}
+
+// BenchmarkAspiration compares the nodes alpha/beta searches choosing
+// a move with aspiration windows to searching the full window, both
+// one search at the full depth, the way ChooseMove does by default,
+// and deepening iteratively to it, the way it does with a Clock. An op
+// is a search of each of a few positions; nodes/op is the number to
+// look at.
+func BenchmarkAspiration(b *testing.B) {
+	var boards []Board
+	for _, fen := range []string{
+		NewBoard(4).FEN(),
+		"4.4.4.4.2.1/5.5.5.5.4.4 1 0 1",
+		"1.0.6.6.0.6/6.6.1.0.6.0 5 5 1",
+		"2.7.1.0.5.3/0.6.6.1.2.7 4 4 1",
+	} {
+		bd, err := BoardFromFEN(fen)
+		if err != nil {
+			b.Fatal(err)
+		}
+		boards = append(boards, bd)
+	}
+	const depth = 4
+	searches := []struct {
+		name   string
+		search func(ab *AlphaBeta, bd Board)
+	}{
+		{"full window", func(ab *AlphaBeta, bd Board) {
+			ab.ChooseMove(context.Background(), bd, false)
+		}},
+		{"deepening", func(ab *AlphaBeta, bd Board) {
+			ab.newSearch()
+			ab.deepen(context.Background(), bd, false)
+		}},
+		{"aspiration", func(ab *AlphaBeta, bd Board) {
+			ab.AspirationSearch(bd, 0)
+		}},
+	}
+	for _, s := range searches {
+		b.Run(s.name, func(b *testing.B) {
+			ab := NewAlphaBeta(depth)
+			var nodes int64
+			for i := 0; i < b.N; i++ {
+				for _, bd := range boards {
+					s.search(ab, bd)
+					nodes += ab.NodesEvaluated
+				}
+			}
+			b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+		})
+	}
+}
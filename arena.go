@@ -0,0 +1,45 @@
+package kalah
+
+// NodeArena hands out MCTS tree nodes from big slices of them,
+// rather than one heap allocation per node, so a 200000 iteration
+// search makes a few large objects for the garbage collector instead
+// of hundreds of thousands of small ones. Nodes never get freed one
+// at a time: chooseMonteCarlo starts a new arena every move, and an
+// old one goes away once nothing points into it. A subtree kept for
+// the next search keeps the slices its nodes are in.
+type NodeArena struct {
+	pool []Node
+	next int
+}
+
+// maxArenaChunk is the most nodes a NodeArena allocates at a time.
+// A clock can stop a search long before its iterations are up.
+const maxArenaChunk = 1 << 16
+
+// NewNodeArena sets up an arena for size nodes, about the number of
+// iterations a search does, each of which adds at most one node.
+// It can hand out more than size, a chunk at a time.
+func NewNodeArena(size int) *NodeArena {
+	if size > maxArenaChunk {
+		size = maxArenaChunk
+	}
+	if size < 1 {
+		size = 1
+	}
+	return &NodeArena{pool: make([]Node, size)}
+}
+
+// newNode gives a zero Node. A nil arena allocates it on the heap.
+func (a *NodeArena) newNode() *Node {
+	if a == nil {
+		return &Node{}
+	}
+	if a.next == len(a.pool) {
+		// nodes already handed out point into the old pool, keep it
+		a.pool = make([]Node, len(a.pool))
+		a.next = 0
+	}
+	n := &a.pool[a.next]
+	a.next++
+	return n
+}
@@ -0,0 +1,42 @@
+package kalah
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+// BenchmarkNodeArena compares MCTS searches getting their nodes from
+// a NodeArena, the way chooseMonteCarlo does, to allocating each one
+// on the heap, with a nil arena. Besides time and allocations, it
+// reports the garbage collections and GC pause time each search costs.
+func BenchmarkNodeArena(b *testing.B) {
+	const iterations = 50000
+	bd := NewBoard(4)
+	arenas := []struct {
+		name  string
+		arena func() *NodeArena
+	}{
+		{"arena", func() *NodeArena { return NewNodeArena(iterations + 1) }},
+		{"heap", func() *NodeArena { return nil }},
+	}
+	for _, a := range arenas {
+		b.Run(a.name, func(b *testing.B) {
+			p := NewMCTS(iterations, 1.414)
+			p.Seed(809)
+			b.ReportAllocs()
+			runtime.GC()
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			for i := 0; i < b.N; i++ {
+				p.arena = a.arena()
+				if _, err := p.search(context.Background(), bd, bd.LegalMoves(MAXIMIZER), iterations); err != nil {
+					b.Fatal(err)
+				}
+			}
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "GCs/op")
+			b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "GC-pause-ns/op")
+		})
+	}
+}
@@ -0,0 +1,947 @@
+// Package kalah plays the game of Kalah, Wikipedia's rules,
+// choosing moves by alpha/beta minimaxing or by Monte Carlo Tree Search.
+// Programs kalah (human vs computer) and playoff (one algorithm
+// against another) live under cmd/.
+package kalah
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MAXIMIZER, MINIMIZER, UNSET
+// are used to denote which player, and also
+// as indexes into arrays for too-clever output and
+// win/loss indicators.
+const (
+	MAXIMIZER = 1  // Computer plays MAXIMIZER
+	MINIMIZER = -1 // Computer has human play MINIMIZER
+	UNSET     = 0
+	WIN       = 10000
+	LOSS      = -10000
+)
+
+// MaxPits is the most pits per side a board can have. Traditional
+// Kalah, and NewBoard, have 6.
+const MaxPits = 8
+
+// Board - internal representation of a Kalah board. Each side's
+// pits, 6 of them traditionally, come first, then its store.
+// Array entries past the store are always 0.
+type Board struct {
+	maxpits [MaxPits + 1]int
+	minpits [MaxPits + 1]int
+	pits    int // pits per side, not counting the store
+	rules   Rules
+	reverse bool
+	player  int // which player made the move resulting in this configuration
+
+	lastMovePit int  // the pit player played, if player isn't UNSET
+	color       bool // String() uses ANSI escape codes
+	unicode     bool // String() draws boxes around the pits and stores
+}
+
+// ChooserFunction picks a pit for MAXIMIZER to play on bd,
+// and gives the value it thinks the move has. If ctx gets cancelled
+// before it's done, it gives the best move it found so far, and
+// ErrSearchCancelled.
+type ChooserFunction func(ctx context.Context, bd Board, print bool) (bestpit int, bestvalue int, err error)
+
+// ErrSearchCancelled is what a ChooserFunction returns, along with
+// the best move it found so far, when its context got cancelled.
+var ErrSearchCancelled = errors.New("search cancelled")
+
+// NewBoard sets up a traditional 6 pits per side board
+// for the start of a game.
+func NewBoard(stonesPerPit int) Board {
+	return NewBoardPits(6, stonesPerPit)
+}
+
+// NewBoardPits sets up a board with pits pits per side, 1 through
+// MaxPits, for the start of a game. It panics for any other number of
+// pits, callers taking pits from a user should check with ValidPits first.
+func NewBoardPits(pits, stonesPerPit int) Board {
+	return NewBoardSides(pits, stonesPerPit, stonesPerPit)
+}
+
+// NewBoardSides is NewBoardPits, but MAXIMIZER's pits start with
+// maxStones stones each and MINIMIZER's with minStones, for a handicap.
+// Winning still takes more than half of all the stones.
+func NewBoardSides(pits, maxStones, minStones int) Board {
+	if err := ValidPits(pits); err != nil {
+		panic(err)
+	}
+	bd := Board{pits: pits}
+	for i := 0; i < pits; i++ {
+		bd.maxpits[i] = maxStones
+		bd.minpits[i] = minStones
+	}
+	return bd
+}
+
+// ValidPits gives an error unless a board can have pits pits per side.
+func ValidPits(pits int) error {
+	if pits < 1 || pits > MaxPits {
+		return fmt.Errorf("%d pits per side, want 1 to %d", pits, MaxPits)
+	}
+	return nil
+}
+
+// Pits is how many pits each side has, not counting the store.
+func (p Board) Pits() int {
+	return p.pits
+}
+
+// Stones gives the number of stones in one of player's pits,
+// pits 0 through Pits()-1, or player's store, pit Pits().
+func (p Board) Stones(player, pit int) int {
+	if player == MAXIMIZER {
+		return p.maxpits[pit]
+	}
+	return p.minpits[pit]
+}
+
+// Store gives the number of stones in player's store.
+func (p Board) Store(player int) int {
+	return p.Stones(player, p.pits)
+}
+
+// TotalStones counts the stones in both sides' pits and stores.
+// Moves only ever move stones around, so it's the same all game.
+func (p Board) TotalStones() int {
+	total := 0
+	for i := 0; i <= p.pits; i++ {
+		total += p.maxpits[i] + p.minpits[i]
+	}
+	return total
+}
+
+// Player is the player who made the move resulting in this board,
+// UNSET before the first move.
+func (p Board) Player() int {
+	return p.player
+}
+
+// SetPlayer changes who made the most recent move. Useful for
+// getting the player to move right in FEN() after a bonus move.
+func (p *Board) SetPlayer(player int) {
+	p.player = player
+}
+
+// Rules gives the variant of the game MakeMove plays on this board.
+func (p Board) Rules() Rules {
+	return p.rules
+}
+
+// SetRules changes the variant of the game MakeMove plays.
+func (p *Board) SetRules(rules Rules) {
+	p.rules = rules
+}
+
+// SetReverse has String() print the board top-to-bottom reversed,
+// MINIMIZER's pits at the top.
+func (p *Board) SetReverse(reverse bool) {
+	p.reverse = reverse
+}
+
+// SetUnicode has String() draw the board with box drawing characters,
+// every pit's count centered in a box of its own.
+func (p *Board) SetUnicode(unicode bool) {
+	p.unicode = unicode
+}
+
+// SetColor has String() color the board with ANSI escape codes,
+// for a terminal: stores in bold yellow, the pit the last move
+// emptied underlined, and stones that the opponent could capture
+// with their next move in red.
+func (p *Board) SetColor(color bool) {
+	p.color = color
+}
+
+// Equal reports whether two boards have the same stones in the same pits,
+// the same rules, and the same player made the last move.
+// Display orientation doesn't count,
+// EqualOriented compares that too.
+func (p Board) Equal(other Board) bool {
+	return p.pits == other.pits &&
+		p.rules == other.rules &&
+		p.maxpits == other.maxpits &&
+		p.minpits == other.minpits &&
+		p.player == other.player
+}
+
+// EqualOriented is Equal, but the boards have to print the same way, too.
+func (p Board) EqualOriented(other Board) bool {
+	return p.Equal(other) && p.reverse == other.reverse
+}
+
+// Mirror gives the board the way the opponent sees it:
+// MAXIMIZER's pits become MINIMIZER's and vice versa.
+func (p Board) Mirror() Board {
+	return Board{
+		maxpits: p.minpits,
+		minpits: p.maxpits,
+		pits:    p.pits,
+		rules:   p.rules,
+		reverse: p.reverse,
+		player:  -p.player,
+
+		lastMovePit: p.lastMovePit,
+		color:       p.color,
+		unicode:     p.unicode,
+	}
+}
+
+// String prints the board with MAXIMIZER's pits across the top,
+// right to left, and MINIMIZER's pits across the bottom, stores at
+// either end. Pits and stores all get the same width, 2 characters or
+// however many the biggest count needs. Negative counts can only come
+// from a bug, print them as 0 so the board at least lines up.
+// SetUnicode and SetColor change how it looks.
+func (p Board) String() string {
+	return p.layout(strconv.Itoa, false)
+}
+
+// maxStoneDots is the most stones %#v draws one by one.
+const maxStoneDots = 8
+
+// Format makes Board a fmt.Formatter. %v and %s print String(),
+// %+v adds a row of column letters, A for the leftmost column, above
+// the top row of pits, and %#v draws each pit's or store's stones as
+// "●", up to maxStoneDots of them, and counts any more than that.
+func (p Board) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, p.layout(stoneDots, false))
+	case verb == 'v' && f.Flag('+'):
+		io.WriteString(f, p.layout(strconv.Itoa, true))
+	default:
+		io.WriteString(f, p.String())
+	}
+}
+
+// stoneDots is a "●" for every stone, or the count if there's too many.
+func stoneDots(count int) string {
+	if count > maxStoneDots {
+		return strconv.Itoa(count)
+	}
+	return strings.Repeat("●", count)
+}
+
+// layout does String's work, with cell giving what to print for every
+// pit's and store's count, and with labels, column letters on top.
+func (p Board) layout(cell func(count int) string, labels bool) string {
+	top, bot := &p.maxpits, &p.minpits
+	if p.reverse {
+		top, bot = bot, top
+	}
+	n := p.pits
+
+	w := 2
+	for i := 0; i <= n; i++ {
+		for _, count := range [2]int{top[i], bot[i]} {
+			if d := utf8.RuneCountInString(cell(nonNegative(count))); d > w {
+				w = d
+			}
+		}
+	}
+	var atRisk [2][MaxPits]bool // top's, then bot's
+	if p.color {
+		atRisk[0], atRisk[1] = p.atRisk(MAXIMIZER), p.atRisk(MINIMIZER)
+		if p.reverse {
+			atRisk[0], atRisk[1] = atRisk[1], atRisk[0]
+		}
+	}
+	// paint gives side[i]'s cell, and the same with any ANSI colors.
+	paint := func(side *[MaxPits + 1]int, i int) (plain, painted string) {
+		c := cell(nonNegative(side[i]))
+		if !p.color {
+			return c, c
+		}
+		row := 0
+		if side == bot {
+			row = 1
+		}
+		var codes []string
+		switch {
+		case i == n:
+			codes = append(codes, ansiBoldYellow)
+		case atRisk[row][i]:
+			codes = append(codes, ansiRed)
+		}
+		if p.player != UNSET && i == p.lastMovePit && side == p.side(p.player) {
+			codes = append(codes, ansiUnderline)
+		}
+		if codes == nil {
+			return c, c
+		}
+		return c, "\x1b[" + strings.Join(codes, ";") + "m" + c + "\x1b[0m"
+	}
+	if p.unicode {
+		return boxes(top, bot, n, w+2, paint, labels)
+	}
+	pad := func(side *[MaxPits + 1]int, i int) string {
+		plain, painted := paint(side, i)
+		return strings.Repeat(" ", w-utf8.RuneCountInString(plain)) + painted
+	}
+
+	var sb strings.Builder
+	indent := strings.Repeat(" ", w+1)
+	if labels {
+		sb.WriteString(indent)
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&sb, "%*c", w, 'A'+i)
+			if i < n-1 {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(indent)
+	for i := n - 1; i >= 0; i-- {
+		sb.WriteString(pad(top, i))
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+	}
+	// right store one column past the end of the row of pits
+	gap := (w + 1) + n*w + (n - 1) + 1 - w
+	fmt.Fprintf(&sb, "\n%s%s%s\n", pad(top, n), strings.Repeat(" ", gap), pad(bot, n))
+	sb.WriteString(indent)
+	for i := 0; i < n; i++ {
+		sb.WriteString(pad(bot, i))
+		if i < n-1 {
+			sb.WriteByte(' ')
+		}
+	}
+
+	return sb.String()
+}
+
+// boxes draws the board the way layout does with SetUnicode: each
+// pit in a box w wide, its cell centered, a row of them for each side,
+// and a double-lined box for each store, either end.
+func boxes(top, bot *[MaxPits + 1]int, n, w int, paint func(*[MaxPits + 1]int, int) (string, string), labels bool) string {
+	center := func(side *[MaxPits + 1]int, i int) string {
+		plain, painted := paint(side, i)
+		// any extra space goes on the left, where it lines up better with numbers
+		left := (w - utf8.RuneCountInString(plain) + 1) / 2
+		right := w - utf8.RuneCountInString(plain) - left
+		return strings.Repeat(" ", left) + painted + strings.Repeat(" ", right)
+	}
+	rule := func(left, middle, right string) string {
+		return left + strings.Repeat(strings.Repeat("─", w)+middle, n-1) + strings.Repeat("─", w) + right
+	}
+	store := strings.Repeat("═", w)
+	empty := "║" + strings.Repeat(" ", w) + "║"
+
+	var sb strings.Builder
+	if labels {
+		// each letter where a 1 digit count would be, past the
+		// store's box and the row's left edge
+		sb.WriteString(strings.Repeat(" ", w+3+w/2))
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				sb.WriteString(strings.Repeat(" ", w))
+			}
+			sb.WriteRune(rune('A' + i))
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("╔" + store + "╗" + rule("┌", "┬", "┐") + "╔" + store + "╗\n")
+	sb.WriteString(empty + "│")
+	for i := n - 1; i >= 0; i-- {
+		sb.WriteString(center(top, i) + "│")
+	}
+	sb.WriteString(empty + "\n")
+	sb.WriteString("║" + center(top, n) + "║" + rule("├", "┼", "┤") + "║" + center(bot, n) + "║\n")
+	sb.WriteString(empty + "│")
+	for i := 0; i < n; i++ {
+		sb.WriteString(center(bot, i) + "│")
+	}
+	sb.WriteString(empty + "\n")
+	sb.WriteString("╚" + store + "╝" + rule("└", "┴", "┘") + "╚" + store + "╝")
+	return sb.String()
+}
+
+// The ANSI SGR parameters SetColor's boards use.
+const (
+	ansiBoldYellow = "1;33"
+	ansiUnderline  = "4"
+	ansiRed        = "31"
+)
+
+// side gives player's pits and store.
+func (p *Board) side(player int) *[MaxPits + 1]int {
+	if player == MAXIMIZER {
+		return &p.maxpits
+	}
+	return &p.minpits
+}
+
+// atRisk gives which of player's pits have stones that the opponent
+// could capture with one move, as things stand.
+func (p *Board) atRisk(player int) (risk [MaxPits]bool) {
+	own, opp := p.side(player), p.side(-player)
+	n := p.pits
+	for pit := 0; pit < n; pit++ {
+		if opp[pit] > 0 && p.captures(-player, pit) {
+			last := (pit + opp[pit]) % (2*n + 1)
+			risk[n-1-last] = own[n-1-last] > 0
+		}
+	}
+	return risk
+}
+
+// Describe tells what the board looks like to player, in words, for a
+// screen reader or a log, rather than String's grid:
+//
+//	Your pits: A=4 B=4 C=0 D=3 E=5 F=4, your store: 7. Opponent pits: a=2 b=4 c=4 d=4 e=3 f=0, opponent store: 8. Available moves: A, B, D, E, F.
+//
+// player's pits get letters from pit 0, A, the same as %+v's column
+// letters for MINIMIZER's pits on a board that isn't reversed, and
+// each of the opponent's pits the lower case letter of the pit across
+// from it.
+func (p Board) Describe(player int) string {
+	own, opp := p.side(player), p.side(-player)
+	n := p.pits
+	var sb strings.Builder
+	sb.WriteString("Your pits:")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, " %c=%d", 'A'+i, own[i])
+	}
+	fmt.Fprintf(&sb, ", your store: %d. Opponent pits:", own[n])
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, " %c=%d", 'a'+i, opp[n-1-i])
+	}
+	fmt.Fprintf(&sb, ", opponent store: %d. Available moves: ", opp[n])
+	var buf [MaxPits]int
+	moves := p.appendLegalMoves(buf[:0], player)
+	if len(moves) == 0 {
+		sb.WriteString("none")
+	}
+	for i, pit := range moves {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteByte(byte('A' + pit))
+	}
+	sb.WriteByte('.')
+	return sb.String()
+}
+
+func nonNegative(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// boardJSON has exported, named versions of Board's fields,
+// for a human-readable, version-tolerant JSON encoding.
+// Each side's pits come first, then its store, so the
+// number of pits is one less than the length of either side.
+type boardJSON struct {
+	Maxpits   []int `json:"maxpits"`
+	Minpits   []int `json:"minpits"`
+	Avalanche bool  `json:"avalanche,omitempty"`
+	NoCapture bool  `json:"no_capture,omitempty"`
+	Reverse   bool  `json:"reverse"`
+	Player    int   `json:"player"`
+}
+
+// MarshalJSON gets used by encoding/json
+func (p Board) MarshalJSON() ([]byte, error) {
+	return json.Marshal(boardJSON{
+		Maxpits:   p.maxpits[:p.pits+1],
+		Minpits:   p.minpits[:p.pits+1],
+		Avalanche: p.rules.Avalanche,
+		NoCapture: p.rules.NoCapture,
+		Reverse:   p.reverse,
+		Player:    p.player,
+	})
+}
+
+// UnmarshalJSON gets used by encoding/json
+func (p *Board) UnmarshalJSON(buf []byte) error {
+	var bj boardJSON
+	if err := json.Unmarshal(buf, &bj); err != nil {
+		return err
+	}
+	if len(bj.Maxpits) != len(bj.Minpits) {
+		return fmt.Errorf("sides have %d and %d pits", len(bj.Maxpits)-1, len(bj.Minpits)-1)
+	}
+	pits := len(bj.Maxpits) - 1
+	if err := ValidPits(pits); err != nil {
+		return err
+	}
+	for i := 0; i <= pits; i++ {
+		if bj.Maxpits[i] < 0 || bj.Minpits[i] < 0 {
+			return fmt.Errorf("negative stone count in pit %d", i)
+		}
+	}
+	switch bj.Player {
+	case MAXIMIZER, MINIMIZER, UNSET:
+	default:
+		return fmt.Errorf("unknown player %d", bj.Player)
+	}
+	*p = Board{pits: pits, rules: Rules{Avalanche: bj.Avalanche, NoCapture: bj.NoCapture}}
+	copy(p.maxpits[:], bj.Maxpits)
+	copy(p.minpits[:], bj.Minpits)
+	p.reverse = bj.Reverse
+	p.player = bj.Player
+	return nil
+}
+
+// CanonicalString gives a compact representation of the stones
+// on the board, suitable for use as a map key: MAXIMIZER's pits
+// and store, a slash, MINIMIZER's pits and store, "4,4,4,4,4,4,0/4,4,4,4,4,4,0"
+// for a fresh 6-pit, 4-stone game.
+// Display orientation and which player moved last don't figure in.
+func (p Board) CanonicalString() string {
+	return joinCounts(p.maxpits[:p.pits+1], ",") + "/" + joinCounts(p.minpits[:p.pits+1], ",")
+}
+
+// joinCounts writes stone counts with sep between them.
+func joinCounts(counts []int, sep string) string {
+	var sb strings.Builder
+	for i, n := range counts {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(strconv.Itoa(n))
+	}
+	return sb.String()
+}
+
+// FEN gives a compact, copy-and-paste-able representation of
+// the board, like chess's Forsyth-Edwards Notation: MAXIMIZER's pits,
+// a slash, MINIMIZER's pits, then MAXIMIZER's store, MINIMIZER's store
+// and the player to move, 1 for MAXIMIZER, -1 for MINIMIZER.
+// A fresh 6-pit, 4-stone game is "4.4.4.4.4.4/4.4.4.4.4.4 0 0 1".
+func (p Board) FEN() string {
+	toMove := -p.player
+	if toMove == UNSET {
+		toMove = MAXIMIZER
+	}
+	return fmt.Sprintf("%s/%s %d %d %d",
+		joinCounts(p.maxpits[:p.pits], "."), joinCounts(p.minpits[:p.pits], "."),
+		p.Store(MAXIMIZER), p.Store(MINIMIZER), toMove)
+}
+
+// BoardFromFEN is the inverse of Board.FEN(). The number of
+// pits per side comes from the FEN, both sides have to have the same.
+func BoardFromFEN(s string) (Board, error) {
+	var bd Board
+
+	fields := strings.Fields(s)
+	if len(fields) != 4 {
+		return bd, fmt.Errorf("FEN %q: want 4 fields, have %d", s, len(fields))
+	}
+	sides := strings.Split(fields[0], "/")
+	if len(sides) != 2 {
+		return bd, fmt.Errorf("FEN %q: want 2 sides of pits, have %d", s, len(sides))
+	}
+	bd.pits = len(strings.Split(sides[0], "."))
+	if err := ValidPits(bd.pits); err != nil {
+		return bd, fmt.Errorf("FEN %q: %v", s, err)
+	}
+	for side, pits := range [2]*[MaxPits + 1]int{&bd.maxpits, &bd.minpits} {
+		counts := strings.Split(sides[side], ".")
+		if len(counts) != bd.pits {
+			return bd, fmt.Errorf("FEN %q: want %d pits, have %d", s, bd.pits, len(counts))
+		}
+		for i, count := range counts {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 {
+				return bd, fmt.Errorf("FEN %q: bad stone count %q", s, count)
+			}
+			pits[i] = n
+		}
+	}
+	for i, pits := range [2]*[MaxPits + 1]int{&bd.maxpits, &bd.minpits} {
+		n, err := strconv.Atoi(fields[1+i])
+		if err != nil || n < 0 {
+			return bd, fmt.Errorf("FEN %q: bad store count %q", s, fields[1+i])
+		}
+		pits[bd.pits] = n
+	}
+	toMove, err := strconv.Atoi(fields[3])
+	if err != nil || (toMove != MAXIMIZER && toMove != MINIMIZER) {
+		return bd, fmt.Errorf("FEN %q: bad player to move %q", s, fields[3])
+	}
+	bd.player = -toMove
+
+	return bd, nil
+}
+
+// MakeMove has player pick up the stones in one of their pits 0-5
+// (0 through Pits()-1 on other size boards) and sow them.
+// The player's store is never directly played.
+// It's an error to play a pit that isn't on the board, an empty pit, or
+// for a player other than MAXIMIZER or MINIMIZER. The board doesn't change
+// in any of those cases.
+//
+// Under the avalanche rule, a last stone landing in a pit on player's
+// side that already had stones in it means picking them all up and
+// sowing on from there. That can't go around in circles forever:
+// sowing always goes the same way around, so getting back to any pit
+// means passing player's store, and dropping a stone in it, and
+// there's only so many stones.
+func MakeMove(bd *Board, pit int, player int) (nextplayer int, plydelta int, err error) {
+	var sides [2]*[MaxPits + 1]int
+	n := bd.pits // store is at n, opposite pit i is n-1-i
+
+	if pit < 0 || pit >= n {
+		return 0, 0, fmt.Errorf("invalid pit %d", pit)
+	}
+
+	switch player {
+	case MAXIMIZER:
+		sides[0] = &(bd.maxpits)
+		sides[1] = &(bd.minpits)
+	case MINIMIZER:
+		sides[0] = &(bd.minpits)
+		sides[1] = &(bd.maxpits)
+	default:
+		return 0, 0, fmt.Errorf("invalid player %d", player)
+	}
+
+	S := 0 // side of player is always 0
+	hand := sides[S][pit]
+	if hand == 0 {
+		return 0, 0, fmt.Errorf("player %d move %d, empty pit", player, pit)
+	}
+	sides[S][pit] = UNSET
+	bd.lastMovePit = pit
+
+	nextplayer = -player
+	plydelta = 1
+
+	bonusmove := false
+
+	for i := pit + 1; hand > 0; {
+		// last stone, on player's side, last pit is empty,
+		// and pit across has stones.
+		if hand == 1 && S == 0 && i < n && sides[S][i] == 0 && sides[S^1][n-1-i] > 0 && !bd.rules.NoCapture {
+			sides[S][n] += sides[S^1][n-1-i] + 1
+			sides[S^1][n-1-i] = 0
+			sides[S][i]-- // so no special cases just below
+		}
+		if !(S == 1 && i == n) {
+			sides[S][i]++
+			hand--
+		}
+		if hand == 0 && bd.rules.Avalanche && S == 0 && i < n && sides[S][i] > 1 {
+			hand = sides[S][i]
+			sides[S][i] = UNSET
+		}
+		if i == n {
+			i = 0
+			S ^= 1 // flip to other side of board
+			if hand == 0 {
+				bonusmove = true
+			}
+		} else {
+			i++
+		}
+	}
+	bd.player = player
+	if bonusmove {
+		nextplayer = player
+		plydelta = 0
+	}
+	return nextplayer, plydelta, nil
+}
+
+// sideSums adds up the stones in each side's pits, not the stores.
+func (p *Board) sideSums() (maxsidesum, minsidesum int) {
+	for i := 0; i < p.pits; i++ {
+		maxsidesum += p.maxpits[i]
+		minsidesum += p.minpits[i]
+	}
+	return maxsidesum, minsidesum
+}
+
+// majority gives the player with more than half of all stones in
+// their store, who can't lose from here, or UNSET if neither does.
+// For a new game, half is pits per side * stones per pit.
+// It takes sideSums' results, callers usually need them anyway.
+func (p *Board) majority(maxsidesum, minsidesum int) int {
+	maxstore, minstore := p.maxpits[p.pits], p.minpits[p.pits]
+	half := (maxsidesum + minsidesum + maxstore + minstore) / 2
+	switch {
+	case maxstore > half:
+		return MAXIMIZER
+	case minstore > half:
+		return MINIMIZER
+	}
+	return UNSET
+}
+
+// IsTerminal reports whether the game is over: one player has more than
+// half the stones in their store, or one side has no stones left in its pits.
+// Unlike CheckEnd, it doesn't change the board.
+func (p Board) IsTerminal() bool {
+	maxsidesum, minsidesum := p.sideSums()
+	return maxsidesum == 0 || minsidesum == 0 || p.majority(maxsidesum, minsidesum) != UNSET
+}
+
+// Score gives each player's store count. Once a side has no stones
+// left in its pits, the stones left in the other side's pits count for
+// that side's player, as CheckEnd would sweep them. It doesn't change the board.
+func (p Board) Score() (maxScore, minScore int) {
+	maxScore, minScore = p.Store(MAXIMIZER), p.Store(MINIMIZER)
+	maxsidesum, minsidesum := p.sideSums()
+	if maxsidesum == 0 || minsidesum == 0 {
+		maxScore += maxsidesum
+		minScore += minsidesum
+	}
+	return maxScore, minScore
+}
+
+// PeekEnd says whether the game on bd is over, and who won, UNSET for
+// a tie, the way CheckEnd does, without changing anything. bd is a
+// copy, so speculative callers needn't Clone it first. Stones in the
+// pits of a side that didn't run out count for that side's store, as
+// CheckEnd would sweep them.
+func PeekEnd(bd Board) (end bool, winner int) {
+	maxsidesum, minsidesum := bd.sideSums()
+	if winner = bd.majority(maxsidesum, minsidesum); winner != UNSET {
+		return true, winner
+	}
+	if maxsidesum != 0 && minsidesum != 0 {
+		return false, UNSET
+	}
+	// Ties can happen, winner == 0 in that case, which == UNSET
+	maxScore, minScore := bd.Score()
+	switch {
+	case maxScore > minScore:
+		winner = MAXIMIZER
+	case maxScore < minScore:
+		winner = MINIMIZER
+	}
+	return true, winner
+}
+
+// CheckEnd figures out if the current game board, passed by reference
+// to avoid compiler-generated struct copying, represents a win/loss/tie
+// and for which player. At the end of a game where one side ran out of
+// stones, it sweeps the other side's stones into their store.
+// PeekEnd does the figuring.
+func CheckEnd(bd *Board) (end bool, winner int) {
+	if end, winner = PeekEnd(*bd); end {
+		bd.sweep()
+	}
+	return end, winner
+}
+
+// sweep moves the stones left in the pits into their side's store,
+// if either side has run out. It's the same sweep as Score().
+func (p *Board) sweep() {
+	maxsidesum, minsidesum := p.sideSums()
+	if maxsidesum != 0 && minsidesum != 0 {
+		return
+	}
+	n := p.pits
+	p.maxpits[n] += maxsidesum
+	p.minpits[n] += minsidesum
+	for i := 0; i < n; i++ {
+		p.maxpits[i] = UNSET
+		p.minpits[i] = UNSET
+	}
+}
+
+// ErrNoMoves is what a ChooserFunction returns, with pit -1, for a
+// board where MAXIMIZER has no stones left to play, a game that's
+// already over.
+var ErrNoMoves = errors.New("no legal moves")
+
+// LegalMoves gives player's non-empty pits, in ascending order,
+// or nil if player has no stones left in their pits.
+func (p Board) LegalMoves(player int) []int {
+	var buf [MaxPits]int
+	moves := p.appendLegalMoves(buf[:0], player)
+	if len(moves) == 0 {
+		return nil
+	}
+	return append([]int(nil), moves...)
+}
+
+// MobilityDifference is how many more legal moves MAXIMIZER has than
+// MINIMIZER, negative if MINIMIZER has more. It doesn't allocate,
+// the way counting LegalMoves would.
+func (p Board) MobilityDifference() int {
+	var buf [MaxPits]int
+	return len(p.appendLegalMoves(buf[:0], MAXIMIZER)) - len(p.appendLegalMoves(buf[:0], MINIMIZER))
+}
+
+// appendLegalMoves is LegalMoves for code that can't afford to
+// allocate: it appends player's non-empty pits to moves.
+func (p *Board) appendLegalMoves(moves []int, player int) []int {
+	pits := &p.minpits
+	if player == MAXIMIZER {
+		pits = &p.maxpits
+	}
+	for pit := 0; pit < p.pits; pit++ {
+		if pits[pit] != UNSET {
+			moves = append(moves, pit)
+		}
+	}
+	return moves
+}
+
+// Adjacency gives, for each of player's pits, whether
+// sowing that pit drops a stone in player's store this turn.
+// Entries past Pits()-1 are always false.
+func (p Board) Adjacency(player int) [MaxPits]bool {
+	return p.Reach(player, p.pits)
+}
+
+// Reach gives, for each of player's pits, whether sowing that
+// pit drops a stone in target, one of the 2*Pits()+1 positions sowing
+// passes through (13 on a 6-pit board), in the order it passes them:
+// player's pits 0 through Pits()-1, their store, Pits(), then the
+// opponent's pits, from the opponent's pit 0, Pits()+1, on. Like
+// isCapture, it works from distances around those positions, and
+// leaves the board alone. Neither counts avalanches, which only
+// start once the first pit's stones are all sown.
+func (p Board) Reach(player int, target int) (reach [MaxPits]bool) {
+	pits := &p.minpits
+	if player == MAXIMIZER {
+		pits = &p.maxpits
+	}
+	lap := 2*p.pits + 1
+	for pit := 0; pit < p.pits; pit++ {
+		d := (target - pit + lap) % lap
+		if d == 0 {
+			d = lap // sowing skips the pit it started from until a full lap
+		}
+		reach[pit] = pits[pit] >= d
+	}
+	return reach
+}
+
+// CapturePreview gives how many of the opponent's stones playing pit
+// would capture for player, 0 if it doesn't capture. The capturing
+// stone doesn't count. Like isCapture, it works it out without sowing,
+// and doesn't know about avalanches.
+func (p Board) CapturePreview(player, pit int) int {
+	if pit < 0 || pit >= p.pits || !p.captures(player, pit) {
+		return 0
+	}
+	own, opp := &p.minpits, &p.maxpits
+	if player == MAXIMIZER {
+		own, opp = opp, own
+	}
+	n := p.pits
+	lap := 2*n + 1
+	hand := own[pit]
+	last := (pit + hand) % lap
+	captured := opp[n-1-last]
+	// one more if the sowing dropped a stone in the opposite pit,
+	// n+1+(n-1-last) positions along from own pit 0
+	if d := (n + 1 + n - 1 - last - pit + lap) % lap; d < hand {
+		captured++
+	}
+	return captured
+}
+
+// CaptureThreats counts player's empty pits with stones in the
+// opponent's pit across from them, the pits a last stone could capture
+// from. It doesn't matter whether any of player's moves ends there.
+// Without captures, under the NoCapture rule, there are none.
+func (p Board) CaptureThreats(player int) int {
+	if p.rules.NoCapture {
+		return 0
+	}
+	own, opp := &p.minpits, &p.maxpits
+	if player == MAXIMIZER {
+		own, opp = opp, own
+	}
+	n := p.pits
+	threats := 0
+	for i := 0; i < n; i++ {
+		if own[i] == 0 && opp[n-1-i] > 0 {
+			threats++
+		}
+	}
+	return threats
+}
+
+// captures is isCapture for player's pit on p, false if
+// the rules say there's no capturing.
+func (p *Board) captures(player, pit int) bool {
+	if p.rules.NoCapture {
+		return false
+	}
+	if player == MAXIMIZER {
+		return isCapture(&p.maxpits, &p.minpits, p.pits, pit)
+	}
+	return isCapture(&p.minpits, &p.maxpits, p.pits, pit)
+}
+
+// earnsBonus reports whether player's pit on p has its last stone
+// land in player's store, earning another move. Like isCapture,
+// it doesn't know about avalanches.
+func (p *Board) earnsBonus(player, pit int) bool {
+	own := &p.minpits
+	if player == MAXIMIZER {
+		own = &p.maxpits
+	}
+	hand := own[pit]
+	return hand > 0 && (pit+hand)%(2*p.pits+1) == p.pits
+}
+
+// BonusMoveChain is how many bonus moves in a row player can make,
+// starting with pit, without the opponent moving: 0 if pit doesn't
+// earn another move, 1 if it does but no move after it does, and so
+// on, the longest chain there is. It makes the moves on copies of p.
+func (p Board) BonusMoveChain(pit int, player int) int {
+	bd := p.Clone()
+	next, _, err := MakeMove(&bd, pit, player)
+	if err != nil || next != player {
+		return 0
+	}
+	longest := 0
+	if !bd.IsTerminal() {
+		var moves [MaxPits]int
+		for _, m := range bd.appendLegalMoves(moves[:0], player) {
+			if chain := bd.BonusMoveChain(m, player); chain > longest {
+				longest = chain
+			}
+		}
+	}
+	return 1 + longest
+}
+
+// isCapture works out whether sowing pit captures, without making the
+// move, on a board with n pits per side. Sowing runs through own pits,
+// own store, opponent's pits, 2n+1 positions in all (13 for 6 pits),
+// so the last stone lands at (pit+hand)%(2n+1). An empty pit sows
+// nothing, and captures nothing.
+func isCapture(own, opp *[MaxPits + 1]int, n, pit int) bool {
+	lap := 2*n + 1
+	hand := own[pit]
+	last := (pit + hand) % lap
+	if hand == 0 || last >= n {
+		return false
+	}
+	// The last pit has to be empty before the last stone drops.
+	// A full lap or more drops stones in every pit on the way.
+	if hand > lap || (last != pit && own[last] != 0) {
+		return false
+	}
+	if opp[n-1-last] > 0 {
+		return true
+	}
+	// opposite pit might have been empty, but got stones sown into it
+	k := (n + 1 + n - 1 - last - pit + lap) % lap
+	return k < hand
+}
@@ -0,0 +1,553 @@
+package kalah
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+// TestTotalStonesConserved plays random games, on boards of every size,
+// with enough stones per pit for sowing to wrap all the way around, and
+// checks that no MakeMove or CheckEnd ever makes or loses a stone.
+func TestTotalStonesConserved(t *testing.T) {
+	conserved := func(seed int64, pits, stones uint8, avalanche, noCapture bool) bool {
+		bd := NewBoardPits(1+int(pits)%MaxPits, 1+int(stones)%20)
+		bd.SetRules(Rules{Avalanche: avalanche, NoCapture: noCapture})
+		total := bd.TotalStones()
+		rng := rand.New(rand.NewSource(seed))
+		player := MAXIMIZER
+		for {
+			moves := bd.LegalMoves(player)
+			pit := moves[rng.Intn(len(moves))]
+			next, _, err := MakeMove(&bd, pit, player)
+			if err != nil {
+				t.Logf("%s: %v", bd.FEN(), err)
+				return false
+			}
+			if bd.TotalStones() != total {
+				t.Logf("player %d pit %d: %d stones, want %d, %s", player, pit, bd.TotalStones(), total, bd.FEN())
+				return false
+			}
+			end, _ := CheckEnd(&bd)
+			if bd.TotalStones() != total {
+				t.Logf("CheckEnd: %d stones, want %d, %s", bd.TotalStones(), total, bd.FEN())
+				return false
+			}
+			if end {
+				return true
+			}
+			player = next
+		}
+	}
+	if err := quick.Check(conserved, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+// sowModel is MakeMove for the mover, written out the plain way, on 2n+2
+// positions in sowing order: the mover's pits, the mover's store, the
+// opponent's pits and the opponent's store, which sowing skips. It
+// gives the board after the move, whether it earned a bonus move, and
+// how many times the sowing passed the mover's store.
+func sowModel(b []int, n, pit int, rules Rules) (after []int, bonus bool, laps int) {
+	b = append([]int(nil), b...)
+	hand := b[pit]
+	b[pit] = 0
+	i := pit
+	for {
+		for ; hand > 0; hand-- {
+			if i = (i + 1) % (2*n + 2); i == 2*n+1 {
+				i = 0
+			}
+			if i == n {
+				laps++
+			}
+			b[i]++
+		}
+		if !rules.Avalanche || i >= n || b[i] == 1 {
+			break
+		}
+		hand, b[i] = b[i], 0
+	}
+	if i < n && b[i] == 1 && b[2*n-i] > 0 && !rules.NoCapture {
+		b[n] += b[2*n-i] + 1
+		b[2*n-i], b[i] = 0, 0
+	}
+	return b, i == n, laps
+}
+
+// TestAvalancheEnds plays random avalanche games, with up to 30
+// stones a pit for long avalanches, checking every move against
+// sowModel, and that the game still ends. Every avalanche that goes all
+// the way around the board drops another stone in the mover's store,
+// which is why an avalanche can't go on forever.
+func TestAvalancheEnds(t *testing.T) {
+	ends := func(seed int64, pits, stones uint8, noCapture bool) bool {
+		n := 1 + int(pits)%MaxPits
+		bd := NewBoardPits(n, 1+int(stones)%30)
+		rules := Rules{Avalanche: true, NoCapture: noCapture}
+		bd.SetRules(rules)
+		rng := rand.New(rand.NewSource(seed))
+		player := MAXIMIZER
+		for moves := 0; ; moves++ {
+			// far more moves than any real game takes, in case it never ends
+			if moves > bd.TotalStones()*2*n {
+				t.Logf("%d moves and no end, %s", moves, bd.FEN())
+				return false
+			}
+			own, opp := &bd.maxpits, &bd.minpits
+			if player == MINIMIZER {
+				own, opp = opp, own
+			}
+			before := append(append([]int(nil), own[:n+1]...), opp[:n+1]...)
+			legal := bd.LegalMoves(player)
+			pit := legal[rng.Intn(len(legal))]
+			want, bonus, laps := sowModel(before, n, pit, rules)
+			next, _, err := MakeMove(&bd, pit, player)
+			if err != nil {
+				t.Logf("%s: %v", bd.FEN(), err)
+				return false
+			}
+			got := append(append([]int(nil), own[:n+1]...), opp[:n+1]...)
+			for i := range want {
+				if got[i] != want[i] {
+					t.Logf("player %d pit %d on %v: got %v, want %v", player, pit, before, got, want)
+					return false
+				}
+			}
+			if bonus != (next == player) {
+				t.Logf("player %d pit %d on %v: bonus move %v, want %v", player, pit, before, next == player, bonus)
+				return false
+			}
+			if got[n]-before[n] < laps {
+				t.Logf("player %d pit %d on %v: store went from %d to %d in %d laps", player, pit, before, before[n], got[n], laps)
+				return false
+			}
+			if end, _ := CheckEnd(&bd); end {
+				return bd.IsTerminal()
+			}
+			player = next
+		}
+	}
+	if err := quick.Check(ends, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestAvalanche checks a few avalanches worked out by hand.
+func TestAvalanche(t *testing.T) {
+	tests := []struct {
+		before string
+		pit    int
+		after  string
+		next   int
+	}{
+		// 2 stones into pits 1 and 2, pick up pit 2's 2 into 3 and 4,
+		// pick up pit 4's 2 into 5 and the store: a bonus move
+		{"2.0.1.0.1.0/4.4.4.4.4.4 0 0 1", 0, "0.1.0.1.0.1/4.4.4.4.4.4 1 0 -1", MAXIMIZER},
+		// around the board: 0's 14 stones end up in 1, which has 1
+		// from earlier in the lap, so 2 get picked up, into 2 and 3,
+		// then 3's 2 into 4 and 5, then 5's 2 into the store and
+		// MINIMIZER's pit 0
+		{"14.0.0.0.0.0/0.0.0.0.0.1 0 0 1", 0, "1.0.2.0.2.0/2.1.1.1.1.2 2 0 -1", MINIMIZER},
+		// the last stone in an empty pit, with stones across: a capture
+		{"1.0.0.0.0.1/0.0.0.0.3.0 0 0 1", 0, "0.0.0.0.0.1/0.0.0.0.0.0 4 0 -1", MINIMIZER},
+	}
+	for _, tt := range tests {
+		bd, err := BoardFromFEN(tt.before)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bd.SetRules(Rules{Avalanche: true})
+		next, _, err := MakeMove(&bd, tt.pit, MAXIMIZER)
+		if err != nil {
+			t.Fatalf("%s pit %d: %v", tt.before, tt.pit, err)
+		}
+		if got := bd.FEN(); got != tt.after || next != tt.next {
+			t.Errorf("%s pit %d: got %s, %d next, want %s, %d next", tt.before, tt.pit, got, next, tt.after, tt.next)
+		}
+	}
+}
+
+// TestFENRoundTrip checks that BoardFromFEN gives back the board FEN
+// came from, and FEN the string BoardFromFEN read, for the start of
+// games of every size, and for games part way through.
+func TestFENRoundTrip(t *testing.T) {
+	if got, want := NewBoard(4).FEN(), "4.4.4.4.4.4/4.4.4.4.4.4 0 0 1"; got != want {
+		t.Errorf("new game FEN %q, want %q", got, want)
+	}
+	var boards []Board
+	for pits := 1; pits <= MaxPits; pits++ {
+		bd := NewBoardPits(pits, 3)
+		bd.SetPlayer(MINIMIZER) // so MAXIMIZER is to move, as the FEN says
+		boards = append(boards, bd)
+	}
+	rng := rand.New(rand.NewSource(760))
+	for _, pits := range []int{6, 4, 8} {
+		bd := NewBoardPits(pits, 4)
+		player := MAXIMIZER
+		for ply := 0; ply < 12; ply++ {
+			moves := bd.LegalMoves(player)
+			next, _, err := MakeMove(&bd, moves[rng.Intn(len(moves))], player)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if end, _ := CheckEnd(&bd); end {
+				break
+			}
+			boards = append(boards, bd)
+			player = next
+		}
+	}
+	for _, bd := range boards {
+		fen := bd.FEN()
+		got, err := BoardFromFEN(fen)
+		if err != nil {
+			t.Errorf("%q: %v", fen, err)
+			continue
+		}
+		if !got.Equal(bd) {
+			t.Errorf("%q: got\n%v\nwant\n%v", fen, got, bd)
+		}
+		if got.FEN() != fen {
+			t.Errorf("%q: FEN again is %q", fen, got.FEN())
+		}
+	}
+
+	// a mid-game position, with MINIMIZER to move
+	const mid = "0.5.5.5.5.1/4.4.4.0.5.5 2 3 -1"
+	bd, err := BoardFromFEN(mid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bd.Pits() != 6 || bd.Stones(MAXIMIZER, 1) != 5 || bd.Stones(MINIMIZER, 3) != 0 ||
+		bd.Store(MAXIMIZER) != 2 || bd.Store(MINIMIZER) != 3 || bd.Player() != MAXIMIZER {
+		t.Errorf("%q read as %q, player %d", mid, bd.FEN(), bd.Player())
+	}
+}
+
+// TestBoardFromFENErrors checks that BoardFromFEN says what's
+// wrong with FENs it can't read.
+func TestBoardFromFENErrors(t *testing.T) {
+	for _, fen := range []string{
+		"",
+		"4.4.4.4.4.4/4.4.4.4.4.4 0 0",
+		"4.4.4.4.4.4/4.4.4.4.4.4 0 0 1 1",
+		"4.4.4.4.4.4 0 0 1",
+		"4.4.4/4.4.4/4.4.4 0 0 1",
+		"4.4.4.4.4.4/4.4.4.4.4 0 0 1",
+		"4.4.4.4.4.4.4.4.4/4.4.4.4.4.4.4.4.4 0 0 1",
+		"4.4.4.x.4.4/4.4.4.4.4.4 0 0 1",
+		"4.4.4.-1.4.4/4.4.4.4.4.4 0 0 1",
+		"4.4.4..4.4/4.4.4.4.4.4 0 0 1",
+		"4.4.4.4.4.4/4.4.4.4.4.4 -1 0 1",
+		"4.4.4.4.4.4/4.4.4.4.4.4 0 y 1",
+		"4.4.4.4.4.4/4.4.4.4.4.4 0 0 0",
+		"4.4.4.4.4.4/4.4.4.4.4.4 0 0 2",
+	} {
+		if bd, err := BoardFromFEN(fen); err == nil {
+			t.Errorf("%q: no error, got %q", fen, bd.FEN())
+		}
+	}
+}
+
+// TestLegalMoves checks that LegalMoves gives the non-empty pits in
+// order, the same ones for a player on a board as for the other player
+// on its Mirror, and nil, without allocating, when there are none.
+func TestLegalMoves(t *testing.T) {
+	bd, err := BoardFromFEN("0.3.0.1.0.2/0.0.0.0.0.0 20 22 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bd.LegalMoves(MAXIMIZER); !equalInts(got, []int{1, 3, 5}) {
+		t.Errorf("MAXIMIZER's moves %v, want [1 3 5]", got)
+	}
+	if got := bd.LegalMoves(MINIMIZER); got != nil {
+		t.Errorf("MINIMIZER's moves %#v, want nil", got)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { bd.LegalMoves(MINIMIZER) }); allocs != 0 {
+		t.Errorf("LegalMoves with no moves: %v allocations, want 0", allocs)
+	}
+
+	rng := rand.New(rand.NewSource(767))
+	for i := 0; i < 100; i++ {
+		bd, _ := randomPosition(rng, 1+rng.Intn(MaxPits), 1+rng.Intn(5), rng.Intn(40))
+		mirror := bd.Mirror()
+		for _, player := range []int{MAXIMIZER, MINIMIZER} {
+			moves := bd.LegalMoves(player)
+			if got := mirror.LegalMoves(-player); !equalInts(got, moves) {
+				t.Errorf("%s: player %d's moves %v, but %v on the Mirror", bd.FEN(), player, moves, got)
+			}
+			var want []int
+			for pit := 0; pit < bd.Pits(); pit++ {
+				if bd.Stones(player, pit) > 0 {
+					want = append(want, pit)
+				}
+			}
+			if !equalInts(moves, want) {
+				t.Errorf("%s: player %d's moves %v, want %v", bd.FEN(), player, moves, want)
+			}
+		}
+	}
+}
+
+// equalInts reports whether a and b have the same ints, nil or not.
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// captured makes player's pit on copies of bd, with and without
+// captures, and gives how many more of the opponent's stones are gone
+// from their pits with them: the stones the move really captures.
+func captured(bd Board, player, pit int) int {
+	with, without := bd.Clone(), bd.Clone()
+	rules := bd.Rules()
+	rules.NoCapture = true
+	without.SetRules(rules)
+	if _, _, err := MakeMove(&with, pit, player); err != nil {
+		panic(err)
+	}
+	if _, _, err := MakeMove(&without, pit, player); err != nil {
+		panic(err)
+	}
+	count := 0
+	for i := 0; i < bd.Pits(); i++ {
+		count += without.Stones(-player, i) - with.Stones(-player, i)
+	}
+	return count
+}
+
+// TestCapturePreview checks CapturePreview on each edge of the capture
+// rule, hand worked out, and against the moves themselves.
+func TestCapturePreview(t *testing.T) {
+	tests := []struct {
+		name      string
+		fen       string
+		pit, want int
+		noCapture bool
+	}{
+		{"into the last pit", "0.0.0.0.1.0/3.0.0.0.0.1 0 0 1", 4, 3, false},
+		{"from the last pit, into the store", "0.0.0.0.0.1/3.0.0.0.0.1 0 0 1", 5, 0, false},
+		{"past the opponent's store", "0.0.0.0.0.9/0.0.0.0.2.0 0 0 1", 5, 3, false},
+		{"all the way around, back to the pit played", "13.0.0.0.0.0/0.0.0.0.0.2 0 0 1", 0, 3, false},
+		{"more than a lap", "14.0.0.0.0.0/1.1.1.1.1.1 0 0 1", 0, 0, false},
+		{"empty pit, empty across", "1.0.0.0.0.0/5.5.5.5.0.5 0 0 1", 0, 0, false},
+		{"empty pit, empty across until sowing gets there", "0.0.0.0.0.9/0.0.0.0.0.0 0 0 1", 5, 1, false},
+		{"into a pit that isn't empty", "1.1.0.0.0.0/5.5.5.5.5.5 0 0 1", 0, 0, false},
+		{"no captures", "0.0.0.0.1.0/3.0.0.0.0.1 0 0 1", 4, 0, true},
+		{"an empty pit", "0.0.0.0.1.0/3.0.0.0.0.1 0 0 1", 0, 0, false},
+		{"pits per side, a small board", "1.0.0/0.4.0 0 0 1", 0, 4, false},
+	}
+	for _, tt := range tests {
+		bd, err := BoardFromFEN(tt.fen)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		bd.SetRules(Rules{NoCapture: tt.noCapture})
+		if got := bd.CapturePreview(MAXIMIZER, tt.pit); got != tt.want {
+			t.Errorf("%s: %s pit %d, CapturePreview %d, want %d", tt.name, tt.fen, tt.pit, got, tt.want)
+		}
+		if got := bd.Mirror().CapturePreview(MINIMIZER, tt.pit); got != tt.want {
+			t.Errorf("%s: %s pit %d, CapturePreview %d for MINIMIZER on the Mirror, want %d", tt.name, tt.fen, tt.pit, got, tt.want)
+		}
+		if bd.Stones(MAXIMIZER, tt.pit) > 0 {
+			if got := captured(bd, MAXIMIZER, tt.pit); got != tt.want {
+				t.Errorf("%s: %s pit %d captures %d, want %d", tt.name, tt.fen, tt.pit, got, tt.want)
+			}
+		}
+	}
+	bd := NewBoard(4)
+	for _, pit := range []int{-1, 6, MaxPits} {
+		if got := bd.CapturePreview(MAXIMIZER, pit); got != 0 {
+			t.Errorf("pit %d off the board: CapturePreview %d, want 0", pit, got)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(778))
+	for i := 0; i < 500; i++ {
+		bd, ok := randomPosition(rng, 1+rng.Intn(MaxPits), 1+rng.Intn(12), rng.Intn(40))
+		if !ok {
+			continue
+		}
+		for _, player := range []int{MAXIMIZER, MINIMIZER} {
+			for _, pit := range bd.LegalMoves(player) {
+				if got, want := bd.CapturePreview(player, pit), captured(bd, player, pit); got != want {
+					t.Errorf("%s: player %d pit %d, CapturePreview %d, captures %d", bd.FEN(), player, pit, got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestMobilityDifference checks MobilityDifference at the start, where
+// both sides have every pit to play, and with a single non-empty pit
+// left on one side, and that it changes sign on the Mirror and doesn't
+// allocate.
+func TestMobilityDifference(t *testing.T) {
+	tests := []struct {
+		fen  string
+		want int
+	}{
+		{NewBoard(4).FEN(), 0},
+		{NewBoardPits(4, 3).FEN(), 0},
+		{"0.0.0.5.0.0/4.4.4.4.4.4 15 4 1", -5},
+		{"4.4.4.4.4.4/0.0.0.0.0.5 4 15 1", 5},
+		{"1.0.0.0.0.0/0.0.0.0.0.1 23 23 1", 0},
+		{"1.2.0.3/0.0.5.0 7 6 1", 2},
+	}
+	for _, tt := range tests {
+		bd, err := BoardFromFEN(tt.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bd.MobilityDifference(); got != tt.want {
+			t.Errorf("%s: MobilityDifference %d, want %d", tt.fen, got, tt.want)
+		}
+		if got := bd.Mirror().MobilityDifference(); got != -tt.want {
+			t.Errorf("%s: MobilityDifference %d on the Mirror, want %d", tt.fen, got, -tt.want)
+		}
+		want := len(bd.LegalMoves(MAXIMIZER)) - len(bd.LegalMoves(MINIMIZER))
+		if want != tt.want {
+			t.Errorf("%s: LegalMoves differ by %d, want %d", tt.fen, want, tt.want)
+		}
+	}
+	bd := NewBoard(4)
+	if allocs := testing.AllocsPerRun(100, func() { bd.MobilityDifference() }); allocs != 0 {
+		t.Errorf("MobilityDifference allocates %v times, want none", allocs)
+	}
+}
+
+// TestMakeMoveErrors checks that MakeMove won't play a pit that isn't
+// on the board, an empty pit, or for a player that isn't MAXIMIZER or
+// MINIMIZER, and that the board doesn't change when it won't.
+func TestMakeMoveErrors(t *testing.T) {
+	start, err := BoardFromFEN("4.0.4.4.4.4/4.4.4.4.0.4 4 4 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	small := NewBoardPits(3, 4) // 3 pits a side
+	tests := []struct {
+		name        string
+		bd          Board
+		pit, player int
+	}{
+		{"pit 6, the store", start, 6, MAXIMIZER},
+		{"pit 6 for MINIMIZER", start, 6, MINIMIZER},
+		{"a negative pit", start, -1, MAXIMIZER},
+		{"a pit past the store", start, MaxPits, MAXIMIZER},
+		{"pit 3 of 3, the store", small, 3, MAXIMIZER},
+		{"an empty pit", start, 1, MAXIMIZER},
+		{"an empty pit for MINIMIZER", start, 4, MINIMIZER},
+		{"player UNSET", start, 0, UNSET},
+		{"player 2", start, 0, 2},
+		{"player WIN", start, 0, WIN},
+	}
+	for _, tt := range tests {
+		bd := tt.bd
+		next, delta, err := MakeMove(&bd, tt.pit, tt.player)
+		if err == nil || next != 0 || delta != 0 {
+			t.Errorf("%s: got %d, %d, %v, want an error", tt.name, next, delta, err)
+		}
+		if bd != tt.bd {
+			t.Errorf("%s: board changed from\n%v\nto\n%v", tt.name, tt.bd, bd)
+		}
+	}
+}
+
+// TestReach checks Reach for targets on player's side, their store,
+// and across the board, some of them only reached by sowing all the
+// way around, and against the moves themselves.
+func TestReach(t *testing.T) {
+	tests := []struct {
+		fen            string
+		player, target int
+		want           []bool // pit 0 on
+	}{
+		// the store, 6 away from pit 0, 1 from pit 5
+		{"6.5.4.3.2.1/4.4.4.4.4.4 0 0 1", MAXIMIZER, 6, []bool{true, true, true, true, true, true}},
+		{"5.4.3.2.1.0/4.4.4.4.4.4 0 0 1", MAXIMIZER, 6, []bool{false, false, false, false, false, false}},
+		{"4.4.4.4.4.4/4.4.4.4.4.4 0 0 1", MAXIMIZER, 6, []bool{false, false, true, true, true, true}},
+		// the opponent's pit 0, just past the store, and their pit 5,
+		// the last position before sowing gets back to pit 0
+		{"4.4.4.4.4.2/4.4.4.4.4.4 0 0 1", MAXIMIZER, 7, []bool{false, false, false, true, true, true}},
+		{"12.11.4.4.4.7/4.4.4.4.4.4 0 0 1", MAXIMIZER, 12, []bool{true, true, false, false, false, true}},
+		// own pits: ahead, and behind, around past the opponent's pits
+		{"1.0.0.0.0.0/4.4.4.4.4.4 0 0 1", MAXIMIZER, 1, []bool{true, false, false, false, false, false}},
+		{"0.0.0.0.11.10/4.4.4.4.4.4 0 0 1", MAXIMIZER, 2, []bool{false, false, false, false, true, true}},
+		{"0.0.0.12.13.0/4.4.4.4.4.4 0 0 1", MAXIMIZER, 3, []bool{false, false, false, false, true, false}},
+		{"0.0.0.13.0.0/4.4.4.4.4.4 0 0 1", MAXIMIZER, 3, []bool{false, false, false, true, false, false}},
+		// MINIMIZER, whose pits are the FEN's second part
+		{"4.4.4.4.4.4/0.0.5.0.8.1 0 0 1", MINIMIZER, 6, []bool{false, false, true, false, true, true}},
+		{"4.4.4.4.4.4/0.0.5.0.8.1 0 0 1", MINIMIZER, 9, []bool{false, false, false, false, true, false}},
+		// 4 pits, 9 positions around
+		{"4.3.2.1/0.0.0.0 0 0 1", MAXIMIZER, 4, []bool{true, true, true, true}},
+		{"8.9.0.8/0.0.0.0 0 0 1", MAXIMIZER, 0, []bool{false, true, false, true}},
+	}
+	for _, tt := range tests {
+		bd, err := BoardFromFEN(tt.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reach := bd.Reach(tt.player, tt.target)
+		for pit, want := range tt.want {
+			if reach[pit] != want {
+				t.Errorf("%s: player %d pit %d reaches %d %v, want %v", tt.fen, tt.player, pit, tt.target, reach[pit], want)
+			}
+		}
+		for pit := bd.Pits(); pit < MaxPits; pit++ {
+			if reach[pit] {
+				t.Errorf("%s: pit %d past the board reaches %d", tt.fen, pit, tt.target)
+			}
+		}
+		if tt.target == bd.Pits() && bd.Adjacency(tt.player) != reach {
+			t.Errorf("%s: Adjacency %v, Reach of the store %v", tt.fen, bd.Adjacency(tt.player), reach)
+		}
+	}
+
+	// Without captures or avalanches, sowing only ever adds stones,
+	// except to the pit sown, so a position reached has more stones
+	// after, and the pit sown has any at all.
+	rng := rand.New(rand.NewSource(764))
+	for i := 0; i < 300; i++ {
+		bd, ok := randomPosition(rng, 1+rng.Intn(MaxPits), 1+rng.Intn(8), rng.Intn(30))
+		if !ok {
+			continue
+		}
+		bd.SetRules(Rules{NoCapture: true})
+		n := bd.Pits()
+		for _, player := range []int{MAXIMIZER, MINIMIZER} {
+			stones := func(b Board, pos int) int {
+				switch {
+				case pos < n:
+					return b.Stones(player, pos)
+				case pos == n:
+					return b.Store(player)
+				}
+				return b.Stones(-player, pos-n-1)
+			}
+			for _, pit := range bd.LegalMoves(player) {
+				after := bd.Clone()
+				if _, _, err := MakeMove(&after, pit, player); err != nil {
+					t.Fatal(err)
+				}
+				for target := 0; target < 2*n+1; target++ {
+					want := stones(after, target) > stones(bd, target)
+					if target == pit {
+						want = stones(after, target) > 0
+					}
+					if got := bd.Reach(player, target)[pit]; got != want {
+						t.Errorf("%s: player %d pit %d reaches %d %v, want %v", bd.FEN(), player, pit, target, got, want)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package kalah
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// OpeningBook maps Board.CanonicalString() of a position
+// with MAXIMIZER to move, to the pit MAXIMIZER should play.
+type OpeningBook map[string]int
+
+// LoadOpeningBook reads a JSON opening book
+func LoadOpeningBook(fileName string) (OpeningBook, error) {
+	buf, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var b OpeningBook
+	if err := json.Unmarshal(buf, &b); err != nil {
+		return nil, fmt.Errorf("opening book %s: %v", fileName, err)
+	}
+	return b, nil
+}
+
+// lookup finds the book move for MAXIMIZER in bd, if there is one,
+// and it's a legal move. Works on a nil OpeningBook.
+func (b OpeningBook) lookup(bd Board) (pit int, found bool) {
+	pit, found = b[bd.CanonicalString()]
+	if !found || pit < 0 || pit >= bd.pits || bd.Stones(MAXIMIZER, pit) == UNSET {
+		return 0, false
+	}
+	return pit, true
+}
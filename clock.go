@@ -0,0 +1,148 @@
+package kalah
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimePeriod is one period of a time control: Moves moves in Seconds
+// seconds, with Increment seconds more after every move. Moves of 0
+// means the rest of the game.
+type TimePeriod struct {
+	Moves     int
+	Seconds   float64
+	Increment float64
+}
+
+// ParseTimeControl turns a chess-style time control, like
+// "40/120,20/60", into periods: 40 moves in 120 seconds, then 20 moves
+// in another 60 seconds. A period of only seconds, like "300", is the
+// rest of the game, and "+2" on the end of a period, like "40/120+2",
+// adds 2 seconds after every move in it.
+func ParseTimeControl(str string) ([]TimePeriod, error) {
+	var periods []TimePeriod
+	for _, field := range strings.Split(str, ",") {
+		var tp TimePeriod
+		field = strings.TrimSpace(field)
+		if i := strings.Index(field, "+"); i >= 0 {
+			inc, err := strconv.ParseFloat(field[i+1:], 64)
+			if err != nil || inc < 0 {
+				return nil, fmt.Errorf("time control %q: bad increment %q", str, field[i+1:])
+			}
+			tp.Increment = inc
+			field = field[:i]
+		}
+		if i := strings.Index(field, "/"); i >= 0 {
+			moves, err := strconv.Atoi(field[:i])
+			if err != nil || moves < 1 {
+				return nil, fmt.Errorf("time control %q: bad move count %q", str, field[:i])
+			}
+			tp.Moves = moves
+			field = field[i+1:]
+		}
+		secs, err := strconv.ParseFloat(field, 64)
+		if err != nil || secs <= 0 {
+			return nil, fmt.Errorf("time control %q: bad seconds %q", str, field)
+		}
+		tp.Seconds = secs
+		periods = append(periods, tp)
+	}
+	return periods, nil
+}
+
+// sharesHeldBack is how many moves' worth of time Budget keeps in
+// reserve, in case a search runs long. movesAssumed is how many moves
+// Budget assumes are left when the period is the rest of the game.
+const (
+	sharesHeldBack = 1
+	movesAssumed   = 20
+)
+
+// Clock is one player's time under a time control.
+// MovesRemaining and SecondsRemaining are what's left of the current
+// period, Increment is what every move in it adds back. Once the last
+// period runs out of moves, it starts over, so "40/120,20/60" is 20
+// moves in 60 seconds from move 41 on.
+type Clock struct {
+	MovesRemaining   int // 0 for the rest of the game
+	SecondsRemaining float64
+	Increment        float64
+
+	periods []TimePeriod
+	next    int // period after the current one
+}
+
+// NewClock starts a clock at the first of periods.
+func NewClock(periods []TimePeriod) *Clock {
+	c := &Clock{periods: periods}
+	c.startPeriod()
+	return c
+}
+
+// startPeriod adds the next period's moves and time to the clock.
+// Time left over from the previous period carries over.
+func (c *Clock) startPeriod() {
+	if c.next >= len(c.periods) {
+		c.next = len(c.periods) - 1
+	}
+	tp := c.periods[c.next]
+	c.next++
+	c.MovesRemaining = tp.Moves
+	c.SecondsRemaining += tp.Seconds
+	c.Increment = tp.Increment
+}
+
+// Budget is how long the next move's search should take: an equal
+// share of the time left over the moves left in the period, with a
+// share held back, plus the increment, but never more than half the
+// time left.
+func (c *Clock) Budget() time.Duration {
+	moves := c.MovesRemaining
+	if moves == 0 {
+		moves = movesAssumed
+	}
+	secs := c.SecondsRemaining/float64(moves+sharesHeldBack) + c.Increment
+	if secs > c.SecondsRemaining/2 {
+		secs = c.SecondsRemaining / 2
+	}
+	if secs <= 0 {
+		return 0
+	}
+	return time.Duration(secs * float64(time.Second))
+}
+
+// Spend takes a move that took elapsed off the clock. Bonus moves
+// count the same as any other move.
+func (c *Clock) Spend(elapsed time.Duration) {
+	c.SecondsRemaining -= elapsed.Seconds()
+	if c.Expired() {
+		return
+	}
+	c.SecondsRemaining += c.Increment
+	if c.MovesRemaining > 0 {
+		c.MovesRemaining--
+		if c.MovesRemaining == 0 {
+			c.startPeriod()
+		}
+	}
+}
+
+// Expired reports whether the player has run out of time,
+// and lost the game.
+func (c *Clock) Expired() bool {
+	return c.SecondsRemaining <= 0
+}
+
+// String shows the time left, and the moves left to make in it.
+func (c *Clock) String() string {
+	left := time.Duration(c.SecondsRemaining * float64(time.Second)).Round(time.Millisecond)
+	if c.MovesRemaining == 0 {
+		return fmt.Sprintf("%v for the rest of the game", left)
+	}
+	if c.MovesRemaining == 1 {
+		return fmt.Sprintf("%v for 1 move", left)
+	}
+	return fmt.Sprintf("%v for %d moves", left, c.MovesRemaining)
+}
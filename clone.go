@@ -0,0 +1,11 @@
+//go:build !int8board
+// +build !int8board
+
+package kalah
+
+// Clone gives a copy of the board. Board has only arrays and
+// scalars in it, so the copy doesn't share anything with the original.
+// Building with the int8board tag copies through EncodedBoard instead.
+func (p Board) Clone() Board {
+	return p
+}
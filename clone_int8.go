@@ -0,0 +1,14 @@
+//go:build int8board
+// +build int8board
+
+package kalah
+
+// Clone gives a copy of the board, by way of Encode and Decode, so
+// the stones get copied as bytes. This is the int8board build, which
+// panics on boards with more than MaxEncodedStones in any one place.
+func (p Board) Clone() Board {
+	e := p.Encode()
+	c := Board{pits: p.pits, rules: p.rules, reverse: p.reverse, player: p.player, lastMovePit: p.lastMovePit, color: p.color, unicode: p.unicode}
+	c.Decode(e)
+	return c
+}
@@ -0,0 +1,65 @@
+package kalah
+
+import "testing"
+
+// Sinks keep the compiler from optimizing away the copies
+// that BenchmarkCopy times.
+var (
+	boardSink   Board
+	encodedSink EncodedBoard
+)
+
+// BenchmarkCopy compares copying a game's stones as Board's arrays of
+// int with copying them as an EncodedBoard of int8, and with getting
+// between the two. Clone is whichever the build does, the int8board
+// tag making it Encode and Decode.
+func BenchmarkCopy(b *testing.B) {
+	bd := NewBoard(4)
+	if _, _, err := MakeMove(&bd, 2, MAXIMIZER); err != nil {
+		b.Fatal(err)
+	}
+	e := bd.Encode()
+	b.Run("Board", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			boardSink = bd
+		}
+	})
+	b.Run("EncodedBoard", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			encodedSink = e
+		}
+	})
+	b.Run("Encode", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			encodedSink = bd.Encode()
+		}
+	})
+	b.Run("Decode", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			boardSink.Decode(e)
+		}
+	})
+	b.Run("Clone", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			boardSink = bd.Clone()
+		}
+	})
+	// a search stack's worth, the way alphaBeta keeps a board a ply
+	const plies = 64
+	var boards [plies]Board
+	var encoded [plies]EncodedBoard
+	b.Run("Board stack", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for ply := range boards {
+				boards[ply] = bd
+			}
+		}
+	})
+	b.Run("EncodedBoard stack", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for ply := range encoded {
+				encoded[ply] = e
+			}
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package main
+
+// kalah-server plays kalah over gRPC, the KalahEngine service in
+// kalahpb/kalah.proto, any number of games at once. The computer is
+// MAXIMIZER and the client is MINIMIZER, the human, as in kalah's
+// interactive game. With -tls-cert and -tls-key, it only speaks TLS.
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"kalah"
+	"kalah/kalahpb"
+)
+
+func main() {
+	addrPtr := flag.String("addr", ":50051", "address to listen on")
+	maxDepthPtr := flag.Int("d", 6, "maximum lookahead depth, moves for each side")
+	stoneCountPtr := flag.Int("n", 4, "number of stones per pit, unless a new game asks for some other number")
+	pitsPtr := flag.Int("p", 6, "number of pits per side")
+	monteCarloPtr := flag.Bool("M", false, "MCTS instead of alpha/beta minimax")
+	iterationPtr := flag.Int("i", 200000, "Number of iterations for MCTS")
+	uctkPtr := flag.Float64("U", 1.414, "UCTK factor, MCTS only")
+	certPtr := flag.String("tls-cert", "", "TLS certificate file, PEM")
+	keyPtr := flag.String("tls-key", "", "TLS private key file, PEM")
+	flag.Parse()
+
+	if err := kalah.ValidPits(*pitsPtr); err != nil {
+		log.Fatal(err)
+	}
+	opts := []kalah.Option{
+		kalah.WithDepth(*maxDepthPtr),
+		kalah.WithStonesPerPit(*stoneCountPtr),
+		kalah.WithPits(*pitsPtr),
+	}
+	if *monteCarloPtr {
+		opts = append(opts, kalah.WithMCTS(*iterationPtr, *uctkPtr))
+	}
+
+	var serverOpts []grpc.ServerOption
+	switch {
+	case *certPtr != "" && *keyPtr != "":
+		creds, err := credentials.NewServerTLSFromFile(*certPtr, *keyPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	case *certPtr != "" || *keyPtr != "":
+		log.Fatal("TLS needs both -tls-cert and -tls-key")
+	}
+
+	lis, err := net.Listen("tcp", *addrPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server := grpc.NewServer(serverOpts...)
+	kalahpb.RegisterKalahEngineServer(server, &engineServer{opts: opts, games: make(map[string]*game)})
+	log.Printf("serving KalahEngine on %s", lis.Addr())
+	log.Fatal(server.Serve(lis))
+}
+
+// engineServer is the KalahEngine service. Every game has its own
+// board and computer player, set up by opts. A game goes away gameTTL
+// after the last call for it, or overTTL after the last one once it's
+// over.
+type engineServer struct {
+	kalahpb.UnimplementedKalahEngineServer
+	opts []kalah.Option
+
+	mu     sync.Mutex // for games and lastID, each game has its own lock
+	games  map[string]*game
+	lastID int
+}
+
+type game struct {
+	mu      sync.Mutex // one move at a time
+	id      string
+	bd      kalah.Board
+	chooser kalah.ChooserFunction
+	toMove  int
+	over    bool
+	winner  int
+	expires time.Time // when it gets evicted, engineServer.mu guards it
+}
+
+// Games nobody calls about for gameTTL get evicted, and so do games
+// that are over, sooner, once there's been time to get the result.
+const (
+	gameTTL = time.Hour
+	overTTL = 5 * time.Minute
+)
+
+func (s *engineServer) NewGame(ctx context.Context, req *kalahpb.NewGameRequest) (*kalahpb.GameState, error) {
+	opts := s.opts
+	if req.StonesPerPit < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "%d stones per pit", req.StonesPerPit)
+	}
+	if req.StonesPerPit > 0 {
+		opts = append(opts[:len(opts):len(opts)], kalah.WithStonesPerPit(int(req.StonesPerPit)))
+	}
+	kg := kalah.NewGame(opts...)
+	g := &game{bd: kg.Board, chooser: kg.Chooser, toMove: kalah.MINIMIZER}
+	if req.ComputerFirst {
+		g.toMove = kalah.MAXIMIZER
+	}
+
+	s.mu.Lock()
+	s.evictExpired(time.Now())
+	s.lastID++
+	g.id = strconv.Itoa(s.lastID)
+	g.expires = time.Now().Add(gameTTL)
+	s.games[g.id] = g
+	s.mu.Unlock()
+	return g.state(), nil
+}
+
+func (s *engineServer) MakeMove(ctx context.Context, req *kalahpb.MakeMoveRequest) (*kalahpb.GameState, error) {
+	g, err := s.game(req.GameId)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	defer s.keep(g)
+	if err := g.ready(kalah.MINIMIZER); err != nil {
+		return nil, err
+	}
+	pit := int(req.Pit)
+	if pit < 0 || pit >= g.bd.Pits() || g.bd.Stones(kalah.MINIMIZER, pit) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "pit %d isn't a legal move", pit)
+	}
+	g.play(pit, kalah.MINIMIZER)
+	return g.state(), nil
+}
+
+func (s *engineServer) ComputerMove(ctx context.Context, req *kalahpb.ComputerMoveRequest) (*kalahpb.MoveResponse, error) {
+	g, err := s.game(req.GameId)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	defer s.keep(g)
+	if err := g.ready(kalah.MAXIMIZER); err != nil {
+		return nil, err
+	}
+	before := time.Now()
+	pit, value, err := g.chooser(ctx, g.bd, false)
+	if err == kalah.ErrSearchCancelled {
+		// the client gave up waiting, it's still the computer's move
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	elapsed := time.Since(before)
+	g.play(pit, kalah.MAXIMIZER)
+	return &kalahpb.MoveResponse{
+		Pit:       int32(pit),
+		Value:     int32(value),
+		ElapsedMs: elapsed.Milliseconds(),
+		State:     g.state(),
+	}, nil
+}
+
+// game gives the game with id, NOT_FOUND if there isn't one,
+// or it's been evicted.
+func (s *engineServer) game(id string) (*game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g, ok := s.games[id]; ok && !time.Now().After(g.expires) {
+		return g, nil
+	}
+	delete(s.games, id)
+	return nil, status.Errorf(codes.NotFound, "no game %q", id)
+}
+
+// keep puts off g's eviction, after a call for it.
+// g.mu has to be locked, for g.over.
+func (s *engineServer) keep(g *game) {
+	ttl := gameTTL
+	if g.over {
+		ttl = overTTL
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g.expires = time.Now().Add(ttl)
+}
+
+// evictExpired forgets every game that expires before now.
+// s.mu has to be locked.
+func (s *engineServer) evictExpired(now time.Time) {
+	for id, g := range s.games {
+		if now.After(g.expires) {
+			delete(s.games, id)
+		}
+	}
+}
+
+// ready says why player can't move now, if it can't.
+func (g *game) ready(player int) error {
+	switch {
+	case g.over:
+		return status.Error(codes.FailedPrecondition, "game over")
+	case g.toMove != player:
+		return status.Errorf(codes.FailedPrecondition, "%s's turn", strings.ToLower(side(g.toMove).String()))
+	}
+	return nil
+}
+
+func (g *game) play(pit int, player int) {
+	next, _, err := kalah.MakeMove(&g.bd, pit, player)
+	if err != nil {
+		panic(err) // callers check the pit first
+	}
+	g.toMove = next
+	g.over, g.winner = kalah.CheckEnd(&g.bd)
+}
+
+func (g *game) state() *kalahpb.GameState {
+	st := &kalahpb.GameState{
+		GameId:        g.id,
+		ComputerStore: int32(g.bd.Store(kalah.MAXIMIZER)),
+		HumanStore:    int32(g.bd.Store(kalah.MINIMIZER)),
+		Over:          g.over,
+	}
+	for pit := 0; pit < g.bd.Pits(); pit++ {
+		st.ComputerPits = append(st.ComputerPits, int32(g.bd.Stones(kalah.MAXIMIZER, pit)))
+		st.HumanPits = append(st.HumanPits, int32(g.bd.Stones(kalah.MINIMIZER, pit)))
+	}
+	if g.over {
+		st.Winner = side(g.winner)
+	} else {
+		st.ToMove = side(g.toMove)
+	}
+	return st
+}
+
+// side gives player, MAXIMIZER, MINIMIZER or UNSET, as a Side.
+func side(player int) kalahpb.Side {
+	switch player {
+	case kalah.MAXIMIZER:
+		return kalahpb.Side_COMPUTER
+	case kalah.MINIMIZER:
+		return kalahpb.Side_HUMAN
+	}
+	return kalahpb.Side_NONE
+}
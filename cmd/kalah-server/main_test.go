@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"kalah"
+	"kalah/kalahpb"
+)
+
+// newTestClient serves s over an in-memory connection, and gives a
+// client of it, so calls go through gRPC, status codes and all.
+func newTestClient(t *testing.T, s *engineServer) kalahpb.KalahEngineClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	kalahpb.RegisterKalahEngineServer(server, s)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return kalahpb.NewKalahEngineClient(conn)
+}
+
+// wantCode checks that what failed with code.
+func wantCode(t *testing.T, what string, err error, code codes.Code) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Errorf("%s: %v, want %v", what, err, code)
+	}
+}
+
+// TestStatusCodes checks the codes calls that can't be done fail with:
+// NOT_FOUND for games that never were or got evicted, INVALID_ARGUMENT
+// for bad requests, FAILED_PRECONDITION out of turn or after the end.
+func TestStatusCodes(t *testing.T) {
+	s := &engineServer{opts: []kalah.Option{kalah.WithDepth(2)}, games: make(map[string]*game)}
+	client := newTestClient(t, s)
+	ctx := context.Background()
+
+	_, err := client.MakeMove(ctx, &kalahpb.MakeMoveRequest{GameId: "1", Pit: 0})
+	wantCode(t, "move in no game", err, codes.NotFound)
+	_, err = client.ComputerMove(ctx, &kalahpb.ComputerMoveRequest{GameId: "1"})
+	wantCode(t, "computer move in no game", err, codes.NotFound)
+	_, err = client.NewGame(ctx, &kalahpb.NewGameRequest{StonesPerPit: -1})
+	wantCode(t, "-1 stones per pit", err, codes.InvalidArgument)
+
+	st, err := client.NewGame(ctx, &kalahpb.NewGameRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := st.GameId
+	_, err = client.ComputerMove(ctx, &kalahpb.ComputerMoveRequest{GameId: id})
+	wantCode(t, "computer move on the human's turn", err, codes.FailedPrecondition)
+	for _, pit := range []int32{-1, 6} {
+		_, err = client.MakeMove(ctx, &kalahpb.MakeMoveRequest{GameId: id, Pit: pit})
+		wantCode(t, "move off the board", err, codes.InvalidArgument)
+	}
+	if _, err = client.MakeMove(ctx, &kalahpb.MakeMoveRequest{GameId: id, Pit: 0}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.MakeMove(ctx, &kalahpb.MakeMoveRequest{GameId: id, Pit: 1})
+	wantCode(t, "move on the computer's turn", err, codes.FailedPrecondition)
+
+	// idle too long, gone
+	g, err := s.game(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	g.expires = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+	_, err = client.ComputerMove(ctx, &kalahpb.ComputerMoveRequest{GameId: id})
+	wantCode(t, "computer move in an evicted game", err, codes.NotFound)
+
+	// over, nothing more to play, and gone sooner
+	if st, err = client.NewGame(ctx, &kalahpb.NewGameRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	id = st.GameId
+	bd, err := kalah.BoardFromFEN("0.0.0.1.0.0/0.0.0.0.0.1 23 23 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, err = s.game(id); err != nil {
+		t.Fatal(err)
+	}
+	g.mu.Lock()
+	g.bd = bd // the human's pit 5 ends it
+	g.mu.Unlock()
+	st, err = client.MakeMove(ctx, &kalahpb.MakeMoveRequest{GameId: id, Pit: 5})
+	if err != nil || !st.Over {
+		t.Fatalf("last move: %v, %v, want the game over", st, err)
+	}
+	_, err = client.MakeMove(ctx, &kalahpb.MakeMoveRequest{GameId: id, Pit: 3})
+	wantCode(t, "move after the end", err, codes.FailedPrecondition)
+	_, err = client.ComputerMove(ctx, &kalahpb.ComputerMoveRequest{GameId: id})
+	wantCode(t, "computer move after the end", err, codes.FailedPrecondition)
+	s.mu.Lock()
+	left := time.Until(g.expires)
+	s.mu.Unlock()
+	if left > overTTL {
+		t.Errorf("finished game expires in %v, want %v", left, overTTL)
+	}
+}
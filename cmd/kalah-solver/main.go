@@ -0,0 +1,222 @@
+package main
+
+// kalah-solver reads a board in FEN format, as Board.FEN() writes it,
+// from stdin, and works out the result with best play by both sides,
+// searching all the way to the end of the game.
+// With -perft N, it counts positions 1 through N moves ahead instead.
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"kalah"
+)
+
+// Scores are for the player to move: winIn-n for a win n moves from
+// the end of the game, bonus moves counting, n-winIn for a loss n moves
+// away, 0 for a draw. A quicker win scores higher, and so does a loss
+// that takes longer, the opponent has more chances to go wrong.
+const winIn = 1000 // more moves than any game has
+
+// How a cached score bounds a position's true score: the search that
+// found it either finished, or cut off on its alpha or beta bound.
+const (
+	exact = iota
+	lower // the true score is at least this
+	upper // the true score is at most this
+)
+
+// result is a position's score with best play by both sides,
+// or a bound on it, and the best move, -1 once the game is over.
+type result struct {
+	score int
+	bound int
+	pit   int
+}
+
+// parent turns the score of the player to move after a move into a
+// score for the player who made it, one move further from the end.
+// same is true for a bonus move, the same player moving again.
+func parent(score int, same bool) int {
+	if !same {
+		score = -score
+	}
+	switch {
+	case score > 0:
+		return score - 1
+	case score < 0:
+		return score + 1
+	}
+	return 0
+}
+
+// toChild undoes parent, turning an alpha or beta bound on the score of
+// the player making a move into one for the player to move after it.
+func toChild(bound int, same bool) int {
+	switch {
+	case bound > 0:
+		bound++
+	case bound < 0:
+		bound--
+	}
+	if !same {
+		return -bound
+	}
+	return bound
+}
+
+// child is the position after one of the moves solve tries.
+type child struct {
+	bd    kalah.Board
+	pit   int
+	next  int // who moves after pit
+	order int // higher to search sooner
+}
+
+// solver has a cache of positions already searched, keyed by
+// Board.Hash(), which includes who moved last, so who moves next, too.
+// Endgames have lots of transpositions. Finding the shortest win means
+// searching every reply, so solve is an alpha/beta search on scores
+// that count moves to the end: once a win in n moves turns up, longer
+// lines get cut off, and a cached bound can answer a later search.
+type solver struct {
+	cache map[uint64]result
+	nodes int
+}
+
+func (s *solver) solve(bd kalah.Board, toMove, alpha, beta int) result {
+	bd.SetPlayer(-toMove)
+	key := bd.Hash()
+	cached, ok := s.cache[key]
+	if ok && (cached.bound == exact ||
+		cached.bound == lower && cached.score >= beta ||
+		cached.bound == upper && cached.score <= alpha) {
+		return cached
+	}
+	s.nodes++
+
+	end := bd.Clone()
+	if over, winner := kalah.CheckEnd(&end); over {
+		r := result{pit: -1}
+		switch winner {
+		case toMove:
+			r.score = winIn
+		case -toMove:
+			r.score = -winIn
+		}
+		s.cache[key] = r
+		return r
+	}
+
+	// Moves that put the most stones in the store, bonus moves
+	// breaking ties, tend to be best, so try them first: the sooner a
+	// good line turns up, the more get cut off. The move that was best
+	// last time goes first of all.
+	var children [kalah.MaxPits]child
+	n := 0
+	for _, pit := range bd.LegalMoves(toMove) {
+		c := child{bd: bd.Clone(), pit: pit}
+		var err error
+		if c.next, _, err = kalah.MakeMove(&c.bd, pit, toMove); err != nil {
+			log.Fatal(err)
+		}
+		c.order = 2 * (c.bd.Store(toMove) - bd.Store(toMove))
+		if c.next == toMove {
+			c.order++
+		}
+		if ok && pit == cached.pit {
+			c.order = 1 << 30
+		}
+		// insertion sort, there's at most MaxPits moves
+		i := n
+		for ; i > 0 && children[i-1].order < c.order; i-- {
+			children[i] = children[i-1]
+		}
+		children[i] = c
+		n++
+	}
+	best := result{score: -winIn - 1}
+	a := alpha
+	for _, c := range children[:n] {
+		same := c.next == toMove
+		var r result
+		if same {
+			r = s.solve(c.bd, c.next, toChild(a, true), toChild(beta, true))
+		} else {
+			r = s.solve(c.bd, c.next, toChild(beta, false), toChild(a, false))
+		}
+		if score := parent(r.score, same); score > best.score {
+			best.score, best.pit = score, c.pit
+			if score > a {
+				a = score
+			}
+			if a >= beta {
+				break
+			}
+		}
+	}
+	switch {
+	case best.score <= alpha:
+		best.bound = upper
+	case best.score >= beta:
+		best.bound = lower
+	}
+	s.cache[key] = best
+	return best
+}
+
+func main() {
+	perftPtr := flag.Int("perft", 0, "count positions 1 through this many moves ahead, don't solve")
+	flag.Parse()
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			log.Fatal(err)
+		}
+		log.Fatal("no board on stdin")
+	}
+	bd, err := kalah.BoardFromFEN(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		log.Fatal(err)
+	}
+	toMove := -bd.Player()
+
+	if *perftPtr > 0 {
+		for depth := 1; depth <= *perftPtr; depth++ {
+			fmt.Printf("perft %d: %d\n", depth, kalah.Perft(bd, toMove, depth))
+		}
+		return
+	}
+
+	s := &solver{cache: make(map[uint64]result)}
+	r := s.solve(bd, toMove, -winIn, winIn)
+
+	fmt.Printf("%v\n", bd)
+	switch {
+	case r.score > 0:
+		fmt.Printf("Win in %d moves\n", winIn-r.score)
+	case r.score < 0:
+		fmt.Printf("Loss in %d moves\n", winIn+r.score)
+	default:
+		fmt.Printf("Draw\n")
+	}
+
+	// Follow the best moves back out of the cache.
+	player := toMove
+	for r.pit >= 0 {
+		fmt.Printf("player %2d plays %d\n", player, r.pit)
+		next, _, err := kalah.MakeMove(&bd, r.pit, player)
+		if err != nil {
+			log.Fatal(err)
+		}
+		player = next
+		r = s.solve(bd, player, -winIn, winIn)
+	}
+	maxScore, minScore := bd.Score()
+	fmt.Printf("Final score %d to %d, %d positions searched\n", maxScore, minScore, s.nodes)
+}
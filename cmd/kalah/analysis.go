@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"kalah"
+)
+
+// playedMove is one move of a game, for -analysis.
+type playedMove struct {
+	bd     kalah.Board // just before the move
+	player int
+	pit    int
+}
+
+// analyzeGame has analyst search every position of a game, from the
+// side of whoever moved, and prints a line for each move: what it was
+// worth, the best move, and how much less than the best move's value
+// the played move got. Those are the mistakes, marked with "?".
+func analyzeGame(ctx context.Context, analyst *kalah.AlphaBeta, moves []playedMove) {
+	fmt.Printf("Analysis, values for the player who moved:\n")
+	fmt.Printf("turn  player    pit  value  best  lost\n")
+	for i, m := range moves {
+		bd := m.bd
+		if m.player == kalah.MINIMIZER {
+			bd = bd.Mirror()
+		}
+		values, err := analyst.MoveValues(ctx, bd)
+		if err != nil {
+			fmt.Printf("Analysis stopped: %v\n", err)
+			return
+		}
+		best := m.pit // unless some other move is worth more
+		for pit := 0; pit < bd.Pits(); pit++ {
+			if v, ok := values[pit]; ok && v > values[best] {
+				best = pit
+			}
+		}
+		lost := values[best] - values[m.pit]
+		mark := ""
+		if lost > 0 {
+			mark = " ?"
+		}
+		fmt.Printf("%4d  %-8s  %3d  %5d  %4d  %4d%s\n",
+			i+1, playerName(m.player), m.pit, values[m.pit], best, lost, mark)
+	}
+}
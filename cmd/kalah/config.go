@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"kalah"
+)
+
+// rcFileName is the config file in the user's home directory
+// that has defaults for kalah's flags.
+const rcFileName = ".kalahrc"
+
+// loadConfig sets flags in fs from fileName, before fs parses the
+// command line, so the command line has the last word. fileName
+// is the little bit of TOML it takes to set flags, one per line:
+//
+//	# comment
+//	d = 8
+//	M = true
+//	book = "book.json"
+//
+// Keys are flag names, strings get quotes, numbers and booleans don't.
+// An [eval] section sets -positional-weights one weight at a time:
+//
+//	[eval]
+//	mobility = 1
+//	bonus_moves = 2
+//	capture_threats = 1
+//	far_pit = 0.5
+//	bonus_chain = 1
+//
+// Weights it doesn't have keep their default values.
+// Anything else gets a warning, and gets skipped. No file, no flags.
+func loadConfig(fs *flag.FlagSet, fileName string) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("warning: %v", err)
+		}
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	section := ""
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		var err error
+		switch {
+		case strings.HasPrefix(line, "["):
+			section, err = configSection(line)
+		case section == "eval":
+			err = setEvalWeight(fs, line)
+		case section != "":
+			// configSection already warned about it
+		default:
+			err = setConfigFlag(fs, line)
+		}
+		if err != nil {
+			log.Printf("warning: %s:%d: %v", fileName, lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("warning: %s: %v", fileName, err)
+	}
+}
+
+// configSection gives the name of the section that line, like
+// "[eval]", starts, with an error for any section but eval. The
+// lines in an unknown section still belong to it, not to the flags.
+func configSection(line string) (string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", fmt.Errorf("%q isn't a [section]", line)
+	}
+	section := strings.TrimSpace(line[1 : len(line)-1])
+	if section != "eval" {
+		return section, fmt.Errorf("no section [%s], skipping it", section)
+	}
+	return section, nil
+}
+
+// splitConfigLine splits one line of a config file, comment
+// stripped, into its key and value. A blank line has no key.
+func splitConfigLine(line string) (key, value string, err error) {
+	if line == "" {
+		return "", "", nil
+	}
+	eq := strings.Index(line, "=")
+	if eq < 0 {
+		return "", "", fmt.Errorf("%q isn't key = value", line)
+	}
+	return strings.TrimSpace(line[:eq]), strings.TrimSpace(line[eq+1:]), nil
+}
+
+// setEvalWeight sets the weight that one line of an [eval] section
+// has in the -positional-weights flag.
+func setEvalWeight(fs *flag.FlagSet, line string) error {
+	key, value, err := splitConfigLine(line)
+	if err != nil || key == "" {
+		return err
+	}
+	w := kalah.DefaultPositionalWeights
+	if current := fs.Lookup("positional-weights").Value.String(); current != "" {
+		if w, err = kalah.ParsePositionalWeights(current); err != nil {
+			return err
+		}
+	}
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%s needs a number, not %s", key, value)
+	}
+	switch key {
+	case "mobility":
+		w.Mobility = weight
+	case "bonus_moves":
+		w.BonusMoves = weight
+	case "capture_threats":
+		w.CaptureThreats = weight
+	case "far_pit":
+		w.FarPit = weight
+	case "bonus_chain":
+		w.BonusChain = weight
+	default:
+		return fmt.Errorf("no weight %q in [eval]", key)
+	}
+	return fs.Set("positional-weights", w.String())
+}
+
+// setConfigFlag sets the flag that one line of a config file,
+// comment stripped, has, if it has one.
+func setConfigFlag(fs *flag.FlagSet, line string) error {
+	key, value, err := splitConfigLine(line)
+	if err != nil || key == "" {
+		return err
+	}
+	fl := fs.Lookup(key)
+	if fl == nil {
+		return fmt.Errorf("no flag %q", key)
+	}
+
+	quoted := strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'")
+	_, isString := fl.Value.(flag.Getter).Get().(string)
+	switch {
+	case isString && !quoted:
+		return fmt.Errorf("%s needs a quoted string, not %s", key, value)
+	case !isString && quoted:
+		return fmt.Errorf("%s needs a number or boolean, not a string", key)
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return fmt.Errorf("unterminated string %s", value)
+		}
+		value = value[1 : len(value)-1]
+	case quoted:
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return fmt.Errorf("bad string %s", value)
+		}
+		value = s
+	}
+	if err := fs.Set(key, value); err != nil {
+		return fmt.Errorf("bad value %s for %s: %v", value, key, err)
+	}
+	return nil
+}
+
+// stripComment takes a # comment off line, unless the # is in a string.
+func stripComment(line string) string {
+	var quote rune
+	escaped := false // by a backslash, in a "" string
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// rcFile gives the user's config file name, "" if
+// there's no telling where their home directory is.
+func rcFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, rcFileName)
+}
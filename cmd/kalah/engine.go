@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"kalah"
+)
+
+// engine plays kalah for some other program, over the line protocol
+// PROTOCOL.md describes. The engine is MAXIMIZER, its opponent is
+// MINIMIZER, and each numbers pits from its own side, as MakeMove does.
+type engine struct {
+	opts    []kalah.Option
+	out     io.Writer
+	bd      kalah.Board
+	chooser kalah.ChooserFunction
+	toMove  int // UNSET until the first move, either side can go first
+	started bool
+	over    bool
+	winner  int
+}
+
+// runEngine reads commands from in and answers on out, until "quit"
+// or the end of in. opts set up every game, as kalah's flags do.
+func runEngine(in io.Reader, out io.Writer, opts []kalah.Option) error {
+	e := &engine{opts: opts, out: out}
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "newgame":
+			err = e.newGame(fields[1:])
+		case "move":
+			err = e.move(fields[1:])
+		case "go":
+			err = e.goMove()
+		case "quit":
+			return nil
+		default:
+			err = fmt.Errorf("unknown command %q", fields[0])
+		}
+		if err != nil {
+			fmt.Fprintf(out, "error %v\n", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// newGame starts a game, with the stones per pit args has, if any.
+func (e *engine) newGame(args []string) error {
+	opts := e.opts
+	if len(args) > 0 {
+		stones, err := strconv.Atoi(args[0])
+		if err != nil || stones < 1 {
+			return fmt.Errorf("bad stones per pit %q", args[0])
+		}
+		opts = append(opts[:len(opts):len(opts)], kalah.WithStonesPerPit(stones))
+	}
+	game := kalah.NewGame(opts...)
+	e.bd, e.chooser = game.Board, game.Chooser
+	e.toMove = kalah.UNSET
+	e.started = true
+	e.over = false
+	return nil
+}
+
+// ready says why no one can move now, if they can't.
+func (e *engine) ready(player int) error {
+	switch {
+	case !e.started:
+		return fmt.Errorf("no game, newgame first")
+	case e.over:
+		return fmt.Errorf("game over")
+	case e.toMove == -player:
+		if player == kalah.MAXIMIZER {
+			return fmt.Errorf("opponent's turn")
+		}
+		return fmt.Errorf("engine's turn")
+	}
+	return nil
+}
+
+// move plays the opponent's move.
+func (e *engine) move(args []string) error {
+	if err := e.ready(kalah.MINIMIZER); err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("move takes a pit")
+	}
+	pit, err := strconv.Atoi(args[0])
+	if err != nil || pit < 0 || pit >= e.bd.Pits() || e.bd.Stones(kalah.MINIMIZER, pit) == 0 {
+		return fmt.Errorf("pit %s isn't a legal move", args[0])
+	}
+	e.play(pit, kalah.MINIMIZER)
+	return nil
+}
+
+// goMove has the engine choose its move, and play it.
+func (e *engine) goMove() error {
+	if err := e.ready(kalah.MAXIMIZER); err != nil {
+		return err
+	}
+	pit, _, err := e.chooser(context.Background(), e.bd, false)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "bestmove %d\n", pit)
+	e.play(pit, kalah.MAXIMIZER)
+	return nil
+}
+
+// play makes player's move, and says whose turn it is now,
+// or how the game came out.
+func (e *engine) play(pit int, player int) {
+	next, _, err := kalah.MakeMove(&e.bd, pit, player)
+	if err != nil {
+		panic(err) // callers check the pit first
+	}
+	e.toMove = next
+	if e.over, e.winner = kalah.CheckEnd(&e.bd); e.over {
+		result := "draw"
+		switch e.winner {
+		case kalah.MAXIMIZER:
+			result = "win"
+		case kalah.MINIMIZER:
+			result = "loss"
+		}
+		fmt.Fprintf(e.out, "gameover %s\n", result)
+		return
+	}
+	if next == kalah.MAXIMIZER {
+		fmt.Fprintf(e.out, "turn engine\n")
+	} else {
+		fmt.Fprintf(e.out, "turn opponent\n")
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"kalah"
+)
+
+// TestEngineErrors sends the engine commands it can't carry out, and
+// checks that it says so, then keeps going, until quit.
+func TestEngineErrors(t *testing.T) {
+	in := strings.NewReader(strings.Join([]string{
+		"go",
+		"move 1",
+		"newgame 0",
+		"fly",
+		"newgame 3",
+		"move 6",
+		"move",
+		"move 2",
+		"go",
+		"",
+		"quit",
+		"go",
+	}, "\n"))
+	var out bytes.Buffer
+	if err := runEngine(in, &out, []kalah.Option{kalah.WithDepth(2)}); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		"error no game, newgame first",
+		"error no game, newgame first",
+		`error bad stones per pit "0"`,
+		`error unknown command "fly"`,
+		"error pit 6 isn't a legal move",
+		"error move takes a pit",
+		"turn engine",
+		"bestmove ",
+		"turn ", // a bonus move is the engine's turn again
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(got), len(want), out.String())
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("line %d: got %q, want %q", i+1, got[i], want[i])
+		}
+	}
+}
+
+// TestEngineGame plays whole games against the engine over a pipe,
+// the way tournament software would, with random moves for the
+// opponent. It keeps its own board, to choose legal moves, and checks
+// that the engine's moves are legal, that its turn lines say who moves
+// next, and that it says how the game came out.
+func TestEngineGame(t *testing.T) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- runEngine(inR, outW, []kalah.Option{kalah.WithDepth(2)})
+		outW.Close()
+	}()
+	lines := bufio.NewScanner(outR)
+	send := func(cmd string) {
+		if _, err := fmt.Fprintln(inW, cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func() string {
+		if !lines.Scan() {
+			t.Fatalf("engine stopped answering: %v", lines.Err())
+		}
+		return lines.Text()
+	}
+
+	rng := rand.New(rand.NewSource(788))
+	for game := 0; game < 4; game++ {
+		send("newgame 4")
+		bd := kalah.NewBoard(4)
+		player := kalah.MINIMIZER // the opponent first, every other game
+		if game%2 == 1 {
+			player = kalah.MAXIMIZER
+		}
+		for over := false; !over; {
+			var pit int
+			if player == kalah.MAXIMIZER {
+				send("go")
+				line := read()
+				var err error
+				if pit, err = strconv.Atoi(strings.TrimPrefix(line, "bestmove ")); err != nil {
+					t.Fatalf("game %d: got %q, want bestmove", game, line)
+				}
+			} else {
+				moves := bd.LegalMoves(kalah.MINIMIZER)
+				pit = moves[rng.Intn(len(moves))]
+				send("move " + strconv.Itoa(pit))
+			}
+			next, _, err := kalah.MakeMove(&bd, pit, player)
+			if err != nil {
+				t.Fatalf("game %d: %v", game, err)
+			}
+			end, winner := kalah.CheckEnd(&bd)
+			want := "turn opponent"
+			switch {
+			case end && winner == kalah.MAXIMIZER:
+				want = "gameover win"
+			case end && winner == kalah.MINIMIZER:
+				want = "gameover loss"
+			case end:
+				want = "gameover draw"
+			case next == kalah.MAXIMIZER:
+				want = "turn engine"
+			}
+			if line := read(); line != want {
+				t.Fatalf("game %d, after pit %d: got %q, want %q", game, pit, line, want)
+			}
+			player, over = next, end
+		}
+	}
+	send("quit")
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	inW.Close()
+}
@@ -0,0 +1,771 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"kalah"
+)
+
+func main() {
+
+	computerFirstPtr := flag.Bool("C", false, "Computer takes first move")
+	humansPtr := flag.Bool("HH", false, "human against human, no computer, -C has player 1 (top) go first")
+	verbosePtr := flag.Bool("v", false, "verbose MCTS output")
+	maxDepthPtr := flag.Int("d", 6, "maximum lookahead depth, moves for each side")
+	stoneCountPtr := flag.Int("n", 4, "number of stones per pit")
+	handicapPtr := flag.Int("handicap", 0, "human starts with this many more stones per pit than the computer")
+	pitsPtr := flag.Int("p", 6, "number of pits per side")
+	noCapturePtr := flag.Bool("no-capture", false, "no captures, last stones in empty pits stay there")
+	avalanchePtr := flag.Bool("avalanche", false, "avalanche rule, sowing goes on from a last stone's non-empty pit")
+	reversePtr := flag.Bool("R", false, "Reverse printed board, top-to-bottom")
+	colorPtr := flag.Bool("color", false, "color the board for a terminal: stores, the last move, stones open to capture")
+	unicodePtr := flag.Bool("unicode", false, "draw the board with box drawing characters")
+	monteCarloPtr := flag.Bool("M", false, "MCTS instead of alpha/beta minimax")
+	profilePtr := flag.Bool("P", false, "Do CPU profiling")
+	iterationPtr := flag.Int("i", 200000, "Number of iterations for MCTS")
+	iterationTimePtr := flag.Duration("i-time", 0, "MCTS does as many iterations as it can in this long, like 2s, instead of -i")
+	uctkPtr := flag.Float64("U", 1.414, "UCTK factor, MCTS only")
+	ravePtr := flag.Bool("rave", false, "MCTS with Rapid Action Value Estimation")
+	puctPtr := flag.Bool("puct", false, "MCTS selects moves by PUCT, greedy priors, instead of UCB1")
+	rolloutPtr := flag.String("rollout", "random", "MCTS playout moves: random, greedy, or mixed:P, greedy with probability P")
+	var evalWeights string
+	flag.StringVar(&evalWeights, "eval-weights", "2,2,2,2,3,4", "static value weights of computer's 6 pits nearest its store")
+	positionalPtr := flag.String("positional-weights", "", "mobility, bonus move, capture threat, far pit and bonus chain weights, like \"1,2,1,0.5,1\", added to the static value")
+	bookPtr := flag.String("book", "", "opening book JSON file")
+	exportTreePtr := flag.String("export-game-tree", "", "write alpha/beta game tree to Graphviz DOT file")
+	savePtr := flag.String("save", "", "save game to JSON file after every turn")
+	loadPtr := flag.String("load", "", "resume game saved in JSON file")
+	recordPtr := flag.String("record", "", "append every move to file as JSON lines")
+	replayPtr := flag.String("replay", "", "replay game recorded by -record, or a file of moves, one pit per line, and exit")
+	jsonLogPtr := flag.String("json-log", "", "append every move to file as versioned JSON lines, with node counts")
+	selfPlayPtr := flag.Int("self-play", 0, "computer plays itself this many games, logged to the -json-log file, self-play.jsonl by default")
+	selfPlaySidesPtr := flag.String("self-play-sides", "A,M", "-self-play algorithms, player 1 then player 2, A alpha/beta or M MCTS")
+	servePtr := flag.String("serve", "", "serve games over HTTP on this address, like :8080, instead of playing one")
+	protocolPtr := flag.String("protocol", "", "engine to be driven over stdin and stdout, as PROTOCOL.md says, instead of playing")
+	logMovesPtr := flag.String("log-moves", "", "append every move to file as JSON lines, for tail -f")
+	clockPtr := flag.Duration("clock", 0, "each player gets this long for the whole game, like 3m, and loses if it runs out")
+	analysisPtr := flag.Bool("analysis", false, "after the game, show what alpha/beta thinks of every move")
+	accessiblePtr := flag.Bool("accessible", false, "describe the board in words before each of your moves, for screen readers, and take pit letters too")
+	showPVPtr := flag.Bool("show-pv", false, "show the line of play the computer's search expects, with its move")
+	showNodesPtr := flag.Bool("show-nodes", false, "say how many nodes the computer's search reached, with its move")
+	vabPtr := flag.Bool("vab", false, "print every move alpha/beta searches, indented, to stderr")
+	vabMaxDepthPtr := flag.Int("vab-max-depth", 3, "how many moves below the root -vab prints")
+	explainPtr := flag.Bool("explain", false, "explain every alpha/beta move the computer makes")
+	algoPtr := flag.String("algo", "alphabeta", "search algorithm, alphabeta or mtdf, without -M")
+	pvPtr := flag.Bool("pv", false, "Principal Variation Search instead of plain alpha/beta")
+	futilityPtr := flag.Bool("futility", true, "futility pruning near the horizon, plain alpha/beta only")
+	negamaxPtr := flag.Bool("negamax", false, "negamax formulation of plain alpha/beta, same moves")
+	nullMovePtr := flag.Bool("null-move", false, "null-move pruning, plain alpha/beta only")
+	ttPtr := flag.Bool("tt", false, "transposition table, plain alpha/beta and mtdf only")
+	aspirationPtr := flag.Int("aspiration", 0, "iterative deepening with aspiration windows this wide, 0 for none")
+	seedPtr := flag.Int64("seed", 0, "random number seed, for MCTS and the same game again, 0 seeds from the time of day")
+	zobristSeedPtr := flag.Int64("zobrist-seed", kalah.DefaultZobristSeed, "seed for Zobrist hash keys")
+	exportThresholdPtr := flag.Int("export-threshold", 2*kalah.LOSS, "only export game tree nodes with value above this")
+	if rc := rcFile(); rc != "" {
+		loadConfig(flag.CommandLine, rc)
+	}
+	flag.Parse()
+
+	if *profilePtr {
+		os.Remove("kalah.prof")
+		f, err := os.Create("kalah.prof")
+		if err != nil {
+			log.Fatal(err)
+		}
+		pprof.StartCPUProfile(f)
+		defer pprof.StopCPUProfile()
+		defer f.Close()
+	}
+
+	if *zobristSeedPtr != kalah.DefaultZobristSeed {
+		kalah.SetZobristSeed(*zobristSeedPtr)
+	}
+
+	if err := kalah.ValidPits(*pitsPtr); err != nil {
+		log.Fatal(err)
+	}
+	if *handicapPtr < 0 {
+		log.Fatalf("-handicap %d, has to be 0 or more", *handicapPtr)
+	}
+
+	pitWeights, err := kalah.ParsePitWeights(evalWeights)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var positional *kalah.PositionalWeights
+	if *positionalPtr != "" {
+		w, err := kalah.ParsePositionalWeights(*positionalPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		positional = &w
+	}
+
+	opts := []kalah.Option{
+		kalah.WithDepth(*maxDepthPtr),
+		kalah.WithStonesPerPit(*stoneCountPtr),
+		kalah.WithHandicap(*handicapPtr),
+		kalah.WithPits(*pitsPtr),
+		kalah.WithRules(kalah.Rules{Avalanche: *avalanchePtr, NoCapture: *noCapturePtr}),
+		kalah.WithPitWeights(pitWeights),
+		kalah.WithFutility(*futilityPtr),
+	}
+
+	if positional != nil {
+		opts = append(opts, kalah.WithPositionalWeights(*positional))
+	}
+	if *verbosePtr {
+		opts = append(opts, kalah.WithVerbose())
+	}
+
+	// a seed from the time of day gets printed too, to play the game again
+	seed := *seedPtr
+	if seed == 0 {
+		seed = time.Now().UTC().UnixNano()
+	}
+	if *verbosePtr {
+		fmt.Printf("Seed %d\n", seed)
+	}
+	opts = append(opts, kalah.WithSeed(seed))
+
+	if *bookPtr != "" {
+		book, err := kalah.LoadOpeningBook(*bookPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, kalah.WithOpeningBook(book))
+	}
+
+	if *exportTreePtr != "" {
+		opts = append(opts, kalah.WithGameTreeExport(*exportTreePtr, *exportThresholdPtr))
+	}
+
+	player := kalah.MINIMIZER
+	if *computerFirstPtr {
+		player = kalah.MAXIMIZER
+	}
+
+	if *replayPtr != "" {
+		// a move file starts from the board the flags give
+		start := kalah.NewGame(opts...).Board
+		start.SetReverse(*reversePtr)
+		start.SetColor(*colorPtr)
+		start.SetUnicode(*unicodePtr)
+		if err := replay(*replayPtr, start, player, *reversePtr, *humansPtr); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *ravePtr {
+		opts = append(opts, kalah.WithRAVE())
+	}
+	if *puctPtr {
+		opts = append(opts, kalah.WithPUCT())
+	}
+	rollout, err := kalah.ParseRollout(*rolloutPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts = append(opts, kalah.WithRollout(rollout))
+	if *iterationTimePtr > 0 {
+		opts = append(opts, kalah.WithIterationTime(*iterationTimePtr))
+	}
+	switch *algoPtr {
+	case "alphabeta":
+	case "mtdf":
+		opts = append(opts, kalah.WithMTDF())
+	default:
+		log.Fatalf("unknown -algo %q, alphabeta or mtdf", *algoPtr)
+	}
+	if *pvPtr {
+		opts = append(opts, kalah.WithPVSearch())
+	}
+	if *negamaxPtr {
+		opts = append(opts, kalah.WithNegamax())
+	}
+	if *nullMovePtr {
+		opts = append(opts, kalah.WithNullMove())
+	}
+	if *ttPtr {
+		opts = append(opts, kalah.WithTranspositionTable())
+	}
+	if *aspirationPtr > 0 {
+		opts = append(opts, kalah.WithAspiration(*aspirationPtr))
+	}
+	// -M last, everything else applies to both -self-play sides
+	mcts := kalah.WithMCTS(*iterationPtr, *uctkPtr)
+	if *selfPlayPtr > 0 {
+		players, err := newSelfPlayers(*selfPlaySidesPtr, opts, mcts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		logName := *jsonLogPtr
+		if logName == "" {
+			logName = "self-play.jsonl"
+		}
+		jsonLog, err := os.OpenFile(logName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer jsonLog.Close()
+		if err := selfPlay(*selfPlayPtr, players, seed, *computerFirstPtr, jsonLog); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *monteCarloPtr {
+		opts = append(opts, mcts)
+	}
+	if *servePtr != "" {
+		log.Fatal(serveGames(*servePtr, opts))
+	}
+	switch *protocolPtr {
+	case "":
+	case "engine":
+		if err := runEngine(os.Stdin, os.Stdout, opts); err != nil {
+			log.Fatal(err)
+		}
+		return
+	default:
+		log.Fatalf("unknown -protocol %q, only engine", *protocolPtr)
+	}
+
+	game := kalah.NewGame(opts...)
+	bd, chooseMove := game.Board, game.Chooser
+	if (*showPVPtr || *explainPtr) && game.AlphaBeta != nil {
+		game.AlphaBeta.EnableBestLine(true) // -explain's expected reply is the line's second move
+	}
+	if *vabPtr && game.AlphaBeta != nil {
+		game.AlphaBeta.TraceSearch(os.Stderr, *vabMaxDepthPtr)
+	}
+	// Hints get a chooser of their own, set up the same way, so they
+	// don't disturb what the computer's remembers from move to move.
+	hint := kalah.NewGame(append(opts[:len(opts):len(opts)], kalah.WithGameTreeExport("", 0))...).Chooser
+
+	// With -clock, each player has a clock, and the computer's
+	// searches stop once they've used their share of what's left.
+	clocks := map[int]*kalah.Clock{}
+	if *clockPtr > 0 {
+		periods := []kalah.TimePeriod{{Seconds: clockPtr.Seconds()}}
+		clocks[kalah.MAXIMIZER] = kalah.NewClock(periods)
+		clocks[kalah.MINIMIZER] = kalah.NewClock(periods)
+		if game.AlphaBeta != nil {
+			game.AlphaBeta.Clock = clocks[kalah.MAXIMIZER]
+		}
+		if game.MCTS != nil {
+			game.MCTS.Clock = clocks[kalah.MAXIMIZER]
+		}
+	}
+
+	if *loadPtr != "" {
+		bd, err = loadBoard(*loadPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// saved games are always between turns
+		if bd.Player() != kalah.UNSET {
+			player = -bd.Player()
+		}
+	}
+	if *reversePtr {
+		bd.SetReverse(true)
+	}
+	bd.SetColor(*colorPtr)
+	bd.SetUnicode(*unicodePtr)
+
+	var recorder *os.File
+	if *recordPtr != "" {
+		recorder, err = os.OpenFile(*recordPtr, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer recorder.Close()
+	}
+	var moveLog *os.File
+	if *logMovesPtr != "" {
+		moveLog, err = os.OpenFile(*logMovesPtr, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer moveLog.Close()
+	}
+	var jsonLog *os.File
+	if *jsonLogPtr != "" {
+		jsonLog, err = os.OpenFile(*jsonLogPtr, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer jsonLog.Close()
+	}
+
+	var history kalah.GameHistory
+	var played []playedMove // for -analysis, the moves history has
+
+	// SIGINT cancels ctx, which stops a search, or waiting for the
+	// human's move, and ends the game, with the deferred clean up.
+	// A search only stops between the computer's moves at the root,
+	// which can take a while deep down, so a second SIGINT kills.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	inputs := readInputs()
+
+GAMELOOP:
+	for ply := 1; ; ply++ {
+		var pit, value int
+		fmt.Printf("%v\n", bd)
+		before := time.Now()
+		boardBefore := bd
+		boardBefore.SetPlayer(-player) // so FEN() has the right player to move
+		switch {
+		case player == kalah.MINIMIZER || *humansPtr:
+			if *humansPtr {
+				fmt.Printf("%s to move\n", seatName(player))
+			}
+			moveCtx, cancel := ctx, context.CancelFunc(func() {})
+			if clock := clocks[player]; clock != nil {
+				moveCtx, cancel = context.WithTimeout(ctx, timeLeft(clock))
+			}
+			var undo, ok bool
+			pit, undo, ok = readMove(moveCtx, inputs, hint, bd, player, true, *accessiblePtr)
+			cancel()
+			if !ok && (ctx.Err() != nil || moveCtx.Err() == nil) {
+				return
+			}
+			if clock := clocks[player]; clock != nil {
+				clock.Spend(time.Since(before))
+				ok = ok && !clock.Expired()
+			}
+			if !ok {
+				fmt.Printf("Game over, %s ran out of time, %s won\n", sideName(player, *humansPtr), sideName(-player, *humansPtr))
+				break GAMELOOP
+			}
+			if undo {
+				previous, ok := history.Undo(player)
+				if !ok {
+					fmt.Printf("Nothing to undo\n")
+					continue
+				}
+				bd = previous
+				played = played[:history.Len()]
+				fmt.Printf("Undone\n---\n")
+				continue
+			}
+		default: // the computer, MAXIMIZER
+			searching := make(chan struct{})
+			go func() {
+				select {
+				case <-ctx.Done():
+					fmt.Printf("\nStopping the search, Ctrl-C again to quit right away\n")
+				case <-searching:
+				}
+			}()
+			searchCtx, cancel := ctx, context.CancelFunc(func() {})
+			if clock := clocks[player]; clock != nil {
+				searchCtx, cancel = context.WithTimeout(ctx, clock.Budget())
+			}
+			pit, value, err = chooseMove(searchCtx, bd, true)
+			cancel()
+			close(searching)
+			if err == kalah.ErrSearchCancelled && ctx.Err() != nil {
+				fmt.Printf("\nInterrupted\n%v\n", bd)
+				return
+			}
+			if err != nil && err != kalah.ErrSearchCancelled { // out of time, it has a move anyway
+				log.Fatal(err)
+			}
+			et := time.Since(before)
+			if *showNodesPtr {
+				fmt.Printf("Computer chooses %d (%d) [%v, %d nodes]\n", pit, value, et, game.NodesEvaluated())
+			} else {
+				fmt.Printf("Computer chooses %d (%d) [%v]\n", pit, value, et)
+			}
+			if line := game.BestLine(); *showPVPtr && len(line) > 0 {
+				fmt.Printf("PV: %s\n", formatPits(line))
+			}
+			if clock := clocks[player]; clock != nil {
+				clock.Spend(et)
+				if clock.Expired() {
+					fmt.Printf("Game over, computer ran out of time, human won\n")
+					break GAMELOOP
+				}
+				fmt.Printf("Computer has %v left\n", timeLeft(clock).Round(100*time.Millisecond))
+			}
+			if *explainPtr && game.AlphaBeta != nil {
+				if why := game.AlphaBeta.ExplainMove(bd); why != "" {
+					fmt.Printf("%s\n", why)
+				}
+			}
+			fmt.Printf("---\n")
+		}
+		lastPlayer := player
+		history.Push(bd, player)
+		played = append(played, playedMove{bd: bd, player: player, pit: pit})
+		player, _, err = kalah.MakeMove(&bd, pit, player)
+		if err != nil {
+			log.Fatal(err)
+		}
+		gameEnd, winner := kalah.CheckEnd(&bd)
+		if recorder != nil {
+			boardAfter := bd
+			boardAfter.SetPlayer(-player)
+			rec := moveRecord{
+				Ply:     ply,
+				Player:  lastPlayer,
+				Pit:     pit,
+				Elapsed: time.Since(before),
+				Before:  boardBefore.FEN(),
+				After:   boardAfter.FEN(),
+				Next:    player,
+				End:     gameEnd,
+			}
+			if err := rec.write(recorder); err != nil {
+				log.Print(err)
+			}
+		}
+		if moveLog != nil {
+			boardAfter := bd
+			boardAfter.SetPlayer(-player)
+			entry := moveLogEntry{
+				Move:   pit,
+				Player: "computer",
+				Score:  value,
+				TimeMS: time.Since(before).Milliseconds(),
+				Board:  boardAfter.FEN(),
+			}
+			if lastPlayer == kalah.MINIMIZER {
+				entry.Player = "human"
+			}
+			if err := entry.write(moveLog); err != nil {
+				log.Print(err)
+			}
+		}
+		if jsonLog != nil {
+			boardAfter := bd
+			boardAfter.SetPlayer(-player)
+			entry := jsonLogEntry{
+				Version: jsonLogVersion,
+				Turn:    ply,
+				Player:  lastPlayer,
+				Pit:     pit,
+				Board:   boardAfter.FEN(),
+				Value:   value,
+				Elapsed: time.Since(before),
+			}
+			if lastPlayer == kalah.MAXIMIZER {
+				entry.Nodes = game.NodesEvaluated()
+			}
+			if err := writeJSONLine(jsonLog, entry); err != nil {
+				log.Print(err)
+			}
+		}
+		// Only save between turns, so the next player can
+		// be worked out from bd.Player() on loading.
+		if *savePtr != "" && player != lastPlayer {
+			if err := saveBoard(*savePtr, bd); err != nil {
+				log.Print(err)
+			}
+		}
+		if gameEnd {
+			w := "cat"
+			switch {
+			case winner != kalah.UNSET && *humansPtr:
+				w = seatName(winner)
+			case winner == kalah.MINIMIZER:
+				w = "human"
+			case winner == kalah.MAXIMIZER:
+				w = "computer"
+			}
+			fmt.Printf("Game over, %s won\n", w)
+			break
+		}
+	}
+	fmt.Printf("Final:\n%v\n", bd)
+
+	if *analysisPtr {
+		analyst := kalah.NewAlphaBeta(*maxDepthPtr)
+		analyst.PitWeights = pitWeights
+		analyst.Positional = positional
+		analyst.Futility = *futilityPtr
+		analyzeGame(ctx, analyst, played)
+	}
+}
+
+// moveRecord is one line of a -record file. It has the board before
+// and after the move, so analysis doesn't have to re-run MakeMove
+// to know the board at any step of a game.
+type moveRecord struct {
+	Ply     int           `json:"ply"`
+	Player  int           `json:"player"`
+	Pit     int           `json:"pit"`
+	Elapsed time.Duration `json:"elapsed_ns"`
+	Before  string        `json:"before"` // Board.FEN()
+	After   string        `json:"after"`  // after CheckEnd() sweeps stones, if game ended
+	Next    int           `json:"next"`   // player who moves next, same as Player for a bonus move
+	End     bool          `json:"end"`
+}
+
+func (r moveRecord) write(w io.Writer) error {
+	return writeJSONLine(w, r)
+}
+
+// moveLogEntry is one line of a -log-moves file, less detailed
+// than a moveRecord, and meant for people watching a game go by.
+type moveLogEntry struct {
+	Move   int    `json:"move"`
+	Player string `json:"player"` // "computer" or "human"
+	Score  int    `json:"score"`  // value the computer gave its move, 0 for human moves
+	TimeMS int64  `json:"time_ms"`
+	Board  string `json:"board"` // Board.FEN() after the move
+}
+
+// write puts e on w as a single line of JSON. An *os.File doesn't
+// buffer, so the line is in the file as soon as write returns,
+// not just when the game ends.
+func (e moveLogEntry) write(w io.Writer) error {
+	return writeJSONLine(w, e)
+}
+
+// jsonLogVersion goes in every -json-log line. It changes
+// if a field does, so programs reading logs can tell.
+const jsonLogVersion = 1
+
+// jsonLogEntry is one line of a -json-log file, for programs to read,
+// with the node count that neither -record nor -log-moves has.
+type jsonLogEntry struct {
+	Version int           `json:"version"`
+	Turn    int           `json:"turn"`
+	Player  int           `json:"player"` // 1 computer, -1 human
+	Pit     int           `json:"pit"`
+	Board   string        `json:"board"` // Board.FEN() after the move
+	Value   int           `json:"value"` // 0 for human moves
+	Elapsed time.Duration `json:"elapsed_ns"`
+	Nodes   int64         `json:"nodes"` // nodes the computer's search reached, 0 for human moves
+}
+
+// writeJSONLine puts v on w as a single line of JSON.
+func writeJSONLine(w io.Writer, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(buf, '\n'))
+	return err
+}
+
+// replayGame prints the board after every move of a game recorded by -record.
+// More than one game can be in a -record file, they're told apart by ply number.
+func replayGame(fileName string, reverse bool) error {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for {
+		var rec moveRecord
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", fileName, err)
+		}
+		if rec.Ply == 1 {
+			bd, err := kalah.BoardFromFEN(rec.Before)
+			if err != nil {
+				return err
+			}
+			bd.SetReverse(reverse)
+			fmt.Printf("New game:\n%v\n", bd)
+		}
+		bd, err := kalah.BoardFromFEN(rec.After)
+		if err != nil {
+			return err
+		}
+		bd.SetReverse(reverse)
+		w := "computer"
+		if rec.Player == kalah.MINIMIZER {
+			w = "human"
+		}
+		fmt.Printf("---\nPly %d, %s chooses %d [%v]\n%v\n", rec.Ply, w, rec.Pit, rec.Elapsed, bd)
+		if rec.End {
+			fmt.Printf("Game over\n")
+		}
+	}
+}
+
+func saveBoard(fileName string, bd kalah.Board) error {
+	buf, err := json.MarshalIndent(bd, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, append(buf, '\n'), 0644)
+}
+
+func loadBoard(fileName string) (kalah.Board, error) {
+	var bd kalah.Board
+	buf, err := os.ReadFile(fileName)
+	if err != nil {
+		return bd, err
+	}
+	if err := json.Unmarshal(buf, &bd); err != nil {
+		return bd, fmt.Errorf("saved game %s: %v", fileName, err)
+	}
+	return bd, nil
+}
+
+// readMove gets a human player's move, a pit 0-5 (on a 6-pit board)
+// that has stones in it, on player's side of the board,
+// or "u" or "undo" to take back that human's last move.
+// "h" or "?" has hint suggest a move, and asks again.
+// ok is false at the end of the input, or if ctx gets cancelled,
+// when it says goodbye. If ctx has a deadline, the human's clock,
+// the prompt shows the time left, and ok is false once it passes.
+// If accessible, it describes the board in words first, and takes
+// Describe's pit letters, A-F, as well as numbers.
+func readMove(ctx context.Context, inputs <-chan humanInput, hint kalah.ChooserFunction, bd kalah.Board, player int, print, accessible bool) (pit int, undo bool, ok bool) {
+	if accessible {
+		fmt.Printf("%s\n", bd.Describe(player))
+	}
+	for {
+		if deadline, timed := ctx.Deadline(); print && timed {
+			fmt.Printf("Your move, %v left: ", time.Until(deadline).Round(100*time.Millisecond))
+		} else if print {
+			fmt.Printf("Your move: ")
+		}
+		var in humanInput
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				fmt.Printf("\nOut of time.\n")
+			} else {
+				fmt.Printf("\nGoodbye.\n")
+			}
+			return 0, false, false
+		case in = <-inputs:
+		}
+		if in.err == io.EOF {
+			return 0, false, false
+		}
+		if in.err != nil {
+			fmt.Printf("Failed to read: %v\n", in.err)
+			os.Exit(1)
+		}
+		input := in.text
+		if input == "u" || input == "undo" {
+			return 0, true, true
+		}
+		if input == "h" || input == "?" {
+			pit, value, err := computeHint(ctx, hint, bd, player)
+			switch {
+			case err == kalah.ErrSearchCancelled:
+			case err != nil:
+				fmt.Printf("No hint: %v\n", err)
+			default:
+				fmt.Printf("Suggested move: %d (value: %+d)\n", pit, value)
+			}
+			continue
+		}
+		var err error
+		pit, err = strconv.Atoi(input)
+		if accessible && len(input) == 1 && input[0] >= 'A' && input[0] < 'A'+byte(bd.Pits()) {
+			pit, err = int(input[0]-'A'), nil
+		}
+		switch {
+		case err != nil || pit < 0 || pit >= bd.Pits():
+			if print && accessible {
+				fmt.Printf("Choose a letter between A and %c, u to undo, or h for a hint, try again\n", 'A'+bd.Pits()-1)
+			} else if print {
+				fmt.Printf("Choose a number between 0 and %d, u to undo, or h for a hint, try again\n", bd.Pits()-1)
+			}
+		case bd.Stones(player, pit) != kalah.UNSET:
+			return pit, false, true
+		}
+	}
+}
+
+// formatPits writes pits out like "3 2 4 1".
+func formatPits(pits []int) string {
+	buf := make([]byte, 0, 2*len(pits))
+	for i, pit := range pits {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = strconv.AppendInt(buf, int64(pit), 10)
+	}
+	return string(buf)
+}
+
+// timeLeft is how much time clock has left.
+func timeLeft(clock *kalah.Clock) time.Duration {
+	return time.Duration(clock.SecondsRemaining * float64(time.Second))
+}
+
+// sideName names player the way a game over message does.
+func sideName(player int, humans bool) string {
+	switch {
+	case humans:
+		return seatName(player)
+	case player == kalah.MAXIMIZER:
+		return "computer"
+	}
+	return "human"
+}
+
+// seatName names player for -HH games, by where their pits are printed.
+func seatName(player int) string {
+	if player == kalah.MAXIMIZER {
+		return "Player 1 (top)"
+	}
+	return "Player 2 (bottom)"
+}
+
+// computeHint has hint choose player's move on bd, the way the
+// computer chooses its own, from player's side of the board.
+// The value is from player's point of view too.
+func computeHint(ctx context.Context, hint kalah.ChooserFunction, bd kalah.Board, player int) (pit, value int, err error) {
+	if player == kalah.MINIMIZER {
+		bd = bd.Mirror()
+	}
+	return hint(ctx, bd, false)
+}
+
+// humanInput is one word of the human's input, or why there isn't one.
+type humanInput struct {
+	text string
+	err  error
+}
+
+// readInputs reads the human's input in the background, so readMove
+// can stop waiting for it on SIGINT. It stops at the first error.
+func readInputs() <-chan humanInput {
+	inputs := make(chan humanInput)
+	go func() {
+		for {
+			var in humanInput
+			_, in.err = fmt.Scanf("%s\n", &in.text)
+			inputs <- in
+			if in.err != nil {
+				return
+			}
+		}
+	}()
+	return inputs
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"kalah"
+)
+
+// countingWriter counts the Write calls it gets.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+// TestMoveLogFlushed writes moves to a -log-moves file the way a game
+// does, and checks that each one is in the file, a whole line of JSON,
+// as soon as write returns, for tail -f to see, not when the game ends
+// and the file gets closed. -record and -json-log lines go the same
+// way.
+func TestMoveLogFlushed(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "moves.jsonl")
+	moveLog, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer moveLog.Close()
+
+	bd := kalah.NewBoard(4)
+	player := kalah.MAXIMIZER
+	for i, pit := range []int{2, 5, 1, 0} {
+		if player, _, err = kalah.MakeMove(&bd, pit, player); err != nil {
+			t.Fatal(err)
+		}
+		entry := moveLogEntry{Move: pit, Player: "computer", Score: i, TimeMS: 1, Board: bd.FEN()}
+		if err := entry.write(moveLog); err != nil {
+			t.Fatal(err)
+		}
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := bytes.Split(bytes.TrimSuffix(buf, []byte("\n")), []byte("\n"))
+		if len(lines) != i+1 || buf[len(buf)-1] != '\n' {
+			t.Fatalf("after move %d, the file has %q, want %d lines", i+1, buf, i+1)
+		}
+		var got moveLogEntry
+		if err := json.Unmarshal(lines[i], &got); err != nil || got != entry {
+			t.Errorf("move %d: read back %+v, %v, want %+v", i+1, got, err, entry)
+		}
+	}
+
+	// one Write a line: a reader never sees half of one
+	var w countingWriter
+	for i, write := range []func() error{
+		func() error { return moveLogEntry{Board: bd.FEN()}.write(&w) },
+		func() error { return moveRecord{Before: bd.FEN(), After: bd.FEN()}.write(&w) },
+		func() error { return writeJSONLine(&w, jsonLogEntry{Version: jsonLogVersion, Board: bd.FEN()}) },
+	} {
+		if err := write(); err != nil {
+			t.Fatal(err)
+		}
+		if w.writes != i+1 || bytes.Count(w.Bytes(), []byte("\n")) != i+1 {
+			t.Errorf("writer %d: %d writes for %d lines, want one each", i, w.writes, bytes.Count(w.Bytes(), []byte("\n")))
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"kalah"
+)
+
+// replay plays back fileName: a -record file, lines of JSON, or a
+// move file, one pit per line, which replayMoves plays from bd with
+// player going first.
+func replay(fileName string, bd kalah.Board, player int, reverse, humans bool) error {
+	buf, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimSpace(buf); len(trimmed) > 0 && trimmed[0] == '{' {
+		return replayGame(fileName, reverse)
+	}
+	return replayMoves(fileName, buf, bd, player, humans)
+}
+
+// replayMoves makes the moves in buf, the contents of move file
+// fileName, printing the board after each, then the result. Each line
+// is a pit, numbered from the point of view of the player making the
+// move, the way a human types them in. Bonus moves mean the same
+// player moves again, as usual. Blank lines, and anything after a
+// "#", don't count, so a game can have notes. It's an error for a
+// move to be illegal, for moves to run out before the game is over,
+// or to go on after it is, and the last board printed shows where.
+// With humans, the players get -HH's names.
+func replayMoves(fileName string, buf []byte, bd kalah.Board, player int, humans bool) error {
+	fmt.Printf("New game:\n%v\n", bd)
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	ply, line := 0, 0
+	gameEnd, winner := false, kalah.UNSET
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		if gameEnd {
+			return fmt.Errorf("%s:%d: move %q after the game is over", fileName, line, text)
+		}
+		pit, err := strconv.Atoi(text)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %q isn't a pit number", fileName, line, text)
+		}
+		mover := player
+		if player, _, err = kalah.MakeMove(&bd, pit, player); err != nil {
+			return fmt.Errorf("%s:%d: %v", fileName, line, err)
+		}
+		ply++
+		fmt.Printf("---\nPly %d, %s chooses %d\n%v\n", ply, sideName(mover, humans), pit, bd)
+		gameEnd, winner = kalah.CheckEnd(&bd)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %v", fileName, err)
+	}
+	if !gameEnd {
+		return fmt.Errorf("%s: moves ran out after ply %d, before the game was over, %s to move", fileName, ply, sideName(player, humans))
+	}
+	w := "cat"
+	if winner != kalah.UNSET {
+		w = sideName(winner, humans)
+	}
+	fmt.Printf("Game over, %s won\nFinal:\n%v\n", w, bd)
+	return nil
+}
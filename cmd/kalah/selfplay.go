@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"kalah"
+)
+
+// selfPlayer is one side of the computer playing itself. Game.Play
+// gives each side its own copy of the board, like a playoff player,
+// from its own point of view, where it's always MAXIMIZER.
+type selfPlayer struct {
+	name string
+	opts []kalah.Option // what NewGame makes its game from, every game
+	game *kalah.Game
+
+	wins  int
+	moves int
+	nodes int64
+	took  time.Duration
+}
+
+// newSelfPlayers sets up the two sides -self-play-sides names, player 1
+// then player 2: "A" for alpha/beta, "M" for MCTS, which also gets mcts.
+// Both get opts, all the other flags.
+func newSelfPlayers(sides string, opts []kalah.Option, mcts kalah.Option) ([2]*selfPlayer, error) {
+	var players [2]*selfPlayer
+	letters := strings.Split(sides, ",")
+	if len(letters) != 2 {
+		return players, fmt.Errorf("-self-play-sides %q: want two algorithms, like A,M", sides)
+	}
+	for i, letter := range letters {
+		// a full slice expression, so the sides don't share an appended option
+		p := &selfPlayer{opts: opts[:len(opts):len(opts)]}
+		switch strings.ToUpper(strings.TrimSpace(letter)) {
+		case "A":
+			p.name = "alpha/beta"
+		case "M":
+			p.name = "MCTS"
+			p.opts = append(p.opts, mcts)
+		default:
+			return players, fmt.Errorf("-self-play-sides %q: unknown algorithm %q, A for alpha/beta or M for MCTS", sides, letter)
+		}
+		players[i] = p
+	}
+	return players, nil
+}
+
+// selfPlay has the computer play games games against itself, player 1
+// as MAXIMIZER, player 2 as MINIMIZER. Whoever moves first alternates
+// from game to game, player 1 first in the first game if computerFirst.
+// Every move goes to log as a -json-log line, Turn starting over at 1
+// each game, Player 1 or -1 for the side that moved. Game g's random
+// numbers come from seed+g, so no two games are the same, but all of
+// them happen again with the same seed. It prints each game's result
+// as it ends, and how the two sides did after the last.
+func selfPlay(games int, players [2]*selfPlayer, seed int64, computerFirst bool, log io.Writer) error {
+	first := kalah.MINIMIZER
+	if computerFirst {
+		first = kalah.MAXIMIZER
+	}
+	draws, plies := 0, 0
+	for g := 1; g <= games; g++ {
+		var turn int
+		var final kalah.Board
+		var logErr error
+		// new games, so no search tree or table carries over
+		for _, p := range players {
+			p.game = kalah.NewGame(append(p.opts, kalah.WithSeed(seed+int64(g)))...)
+		}
+		// player 1's game plays, player 2's chooser is its opponent
+		game := players[0].game
+		last := time.Now()
+		game.OnMove = func(bd kalah.Board, player, pit, value int) {
+			mover := players[0]
+			if player == kalah.MINIMIZER {
+				mover = players[1]
+			}
+			elapsed := time.Since(last)
+			nodes := mover.game.NodesEvaluated()
+			mover.moves++
+			mover.took += elapsed
+			mover.nodes += nodes
+			turn++
+			entry := jsonLogEntry{
+				Version: jsonLogVersion,
+				Turn:    turn,
+				Player:  player,
+				Pit:     pit,
+				Board:   bd.FEN(),
+				Value:   value,
+				Elapsed: elapsed,
+				Nodes:   nodes,
+			}
+			if err := writeJSONLine(log, entry); err != nil && logErr == nil {
+				logErr = err
+			}
+			last = time.Now()
+		}
+		game.OnEnd = func(bd kalah.Board, winner int) {
+			final = bd
+		}
+		winner, err := game.Play(context.Background(), players[1].game.Chooser, first)
+		if err != nil {
+			return fmt.Errorf("game %d: %v", g, err)
+		}
+		if logErr != nil {
+			return logErr
+		}
+		plies += turn
+
+		result := "draw"
+		switch winner {
+		case kalah.MAXIMIZER:
+			players[0].wins++
+			result = "player 1, " + players[0].name + ", won"
+		case kalah.MINIMIZER:
+			players[1].wins++
+			result = "player 2, " + players[1].name + ", won"
+		default:
+			draws++
+		}
+		fmt.Printf("Game %d: %s, %d to %d\n", g, result, final.Store(kalah.MAXIMIZER), final.Store(kalah.MINIMIZER))
+		first = -first
+	}
+
+	fmt.Printf("%d games, %d draws, %.1f moves a game\n", games, draws, float64(plies)/float64(games))
+	for i, p := range players {
+		if p.moves == 0 {
+			continue
+		}
+		fmt.Printf("player %d, %s: %d wins, %v and %d nodes a move\n",
+			i+1, p.name, p.wins, (p.took / time.Duration(p.moves)).Round(time.Microsecond), p.nodes/int64(p.moves))
+	}
+	return nil
+}
@@ -0,0 +1,447 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"kalah"
+)
+
+// gameServer runs any number of games at once over HTTP, each with
+// its own board and its own computer player, set up by the same
+// options as kalah's command line flags. The human is MINIMIZER,
+// as in the interactive game.
+//
+//	POST /game               new game, body {"computer_first": true} optional
+//	GET  /game/{id}          the game's board and whose turn it is
+//	POST /game/{id}/move     the human's move, body {"pit": 3}, then the computer's
+//	GET  /game/{id}/history  every move so far
+//	GET  /ws/game/{id}       WebSocket, the game's board after every move
+//
+// A game goes away servedGameTTL after the last request for it, or
+// servedOverTTL after the last one once it's over.
+type gameServer struct {
+	opts []kalah.Option
+
+	mu     sync.Mutex // for games and lastID, each game has its own lock
+	games  map[string]*servedGame
+	lastID int
+}
+
+// servedGame is one game on a gameServer.
+type servedGame struct {
+	mu      sync.Mutex // one move at a time
+	id      string
+	bd      kalah.Board
+	chooser kalah.ChooserFunction
+	toMove  int
+	over    bool
+	winner  int
+	history []servedMove
+	expires time.Time // when it gets evicted, gameServer.mu guards it
+
+	// watchers get a gameEvent after every move. They have their
+	// own lock, so watching starts right away, even while the
+	// computer is busy choosing a move with mu locked.
+	watchMu   sync.Mutex
+	watchers  map[chan gameEvent]bool
+	lastEvent gameEvent // what a new watcher gets first
+}
+
+// Games nobody asks about for servedGameTTL get evicted, and so do
+// games that are over, sooner, once there's been time to get the
+// final board and the history.
+const (
+	servedGameTTL = time.Hour
+	servedOverTTL = 5 * time.Minute
+)
+
+// watcherBuffer is how many events a watcher can fall behind by before
+// it gets dropped, so one slow client can't hold up a game.
+const watcherBuffer = 64
+
+// gameEvent is a WebSocket message: the game as GET /game/{id} gives
+// it, after a move, with what the move was worth and how long it took.
+type gameEvent struct {
+	Event string `json:"event"` // "move", "game_over" after the last move, or "error"
+	gameState
+	Value     int    `json:"value"`      // what the computer gave its move, 0 for human moves
+	ElapsedMS int64  `json:"elapsed_ms"` // how long the computer took, 0 for human moves
+	Error     string `json:"error,omitempty"`
+}
+
+// servedMove is one move in a game's history.
+type servedMove struct {
+	Player string `json:"player"` // "computer" or "human"
+	Pit    int    `json:"pit"`
+	Value  int    `json:"value"` // what the computer gave its move, 0 for human moves
+	Board  string `json:"board"` // Board.FEN() after the move
+}
+
+// gameState is what most requests get back.
+type gameState struct {
+	ID     string      `json:"id"`
+	Board  kalah.Board `json:"board"`
+	FEN    string      `json:"fen"`
+	ToMove string      `json:"to_move"` // "computer", "human", or "" once the game is over
+	Over   bool        `json:"over"`
+	Winner string      `json:"winner,omitempty"` // "computer", "human" or "draw"
+	Moves  []int       `json:"computer_moves,omitempty"`
+}
+
+// serveGames runs a gameServer on addr, like ":8080", until it fails.
+func serveGames(addr string, opts []kalah.Option) error {
+	s := &gameServer{opts: opts, games: make(map[string]*servedGame)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/game", s.newGame)
+	mux.HandleFunc("/game/", s.route)
+	mux.HandleFunc("/ws/game/", s.watch)
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  servedReadTimeout,
+		WriteTimeout: servedWriteTimeout,
+	}
+	log.Printf("serving games on %s", addr)
+	return server.ListenAndServe()
+}
+
+func (s *gameServer) newGame(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		httpError(w, http.StatusMethodNotAllowed, "POST to make a new game")
+		return
+	}
+	var req struct {
+		ComputerFirst bool `json:"computer_first"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpError(w, http.StatusBadRequest, "bad JSON: %v", err)
+			return
+		}
+	}
+
+	game := kalah.NewGame(s.opts...)
+	s.mu.Lock()
+	s.lastID++
+	g := &servedGame{
+		id:      strconv.Itoa(s.lastID),
+		bd:      game.Board,
+		chooser: game.Chooser,
+		toMove:  kalah.MINIMIZER,
+	}
+	s.mu.Unlock()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if req.ComputerFirst {
+		g.toMove = kalah.MAXIMIZER
+	}
+	g.watchers = make(map[chan gameEvent]bool)
+	g.lastEvent = gameEvent{Event: "move", gameState: g.state(nil)}
+	s.mu.Lock()
+	s.evictExpired(time.Now())
+	g.expires = time.Now().Add(servedGameTTL)
+	s.games[g.id] = g
+	s.mu.Unlock()
+
+	moves, err := g.computerMoves(r.Context())
+	s.keep(g)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, g.state(moves))
+}
+
+// game gives the game with id, nil if there isn't one,
+// or it's been evicted.
+func (s *gameServer) game(id string) *servedGame {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.games[id]
+	if g != nil && time.Now().After(g.expires) {
+		s.evict(g)
+		return nil
+	}
+	return g
+}
+
+// keep puts off g's eviction, after a request for it.
+// g.mu has to be locked, for g.over.
+func (s *gameServer) keep(g *servedGame) {
+	ttl := servedGameTTL
+	if g.over {
+		ttl = servedOverTTL
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g.expires = time.Now().Add(ttl)
+}
+
+// evictExpired evicts every game that expires before now.
+// s.mu has to be locked.
+func (s *gameServer) evictExpired(now time.Time) {
+	for _, g := range s.games {
+		if now.After(g.expires) {
+			s.evict(g)
+		}
+	}
+}
+
+// evict forgets g, and hangs up on its watchers.
+// s.mu has to be locked.
+func (s *gameServer) evict(g *servedGame) {
+	delete(s.games, g.id)
+	g.watchMu.Lock()
+	defer g.watchMu.Unlock()
+	for ch := range g.watchers {
+		delete(g.watchers, ch)
+		close(ch)
+	}
+}
+
+// route sends /game/{id} and what's under it to the right handler.
+func (s *gameServer) route(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/game/"), "/")
+	g := s.game(parts[0])
+	if g == nil {
+		httpError(w, http.StatusNotFound, "no game %q", parts[0])
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	defer s.keep(g)
+	// A search whose client went away, cancelling it,
+	// starts over with the next request for the game.
+	resumed, err := g.computerMoves(r.Context())
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, g.state(resumed))
+	case len(parts) == 2 && parts[1] == "move" && r.Method == http.MethodPost:
+		if len(resumed) > 0 {
+			httpError(w, http.StatusConflict, "computer's turn, it played %v", resumed)
+			return
+		}
+		g.move(w, r)
+	case len(parts) == 2 && parts[1] == "history" && r.Method == http.MethodGet:
+		writeJSON(w, g.history)
+	default:
+		httpError(w, http.StatusNotFound, "no %s %s", r.Method, r.URL.Path)
+	}
+}
+
+// move makes the human's move, then the computer's, as many as it
+// gets in a row, unless the human earned a bonus move.
+func (g *servedGame) move(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Pit *int `json:"pit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Pit == nil {
+		httpError(w, http.StatusBadRequest, `body should be like {"pit": 3}`)
+		return
+	}
+	if g.over {
+		httpError(w, http.StatusConflict, "game over")
+		return
+	}
+	if g.toMove != kalah.MINIMIZER {
+		httpError(w, http.StatusConflict, "computer's turn")
+		return
+	}
+	pit := *req.Pit
+	if pit < 0 || pit >= g.bd.Pits() || g.bd.Stones(kalah.MINIMIZER, pit) == 0 {
+		httpError(w, http.StatusBadRequest, "pit %d isn't a legal move", pit)
+		return
+	}
+	g.makeMove(pit, 0, 0)
+	moves, err := g.computerMoves(r.Context())
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	writeJSON(w, g.state(moves))
+}
+
+// computerMoves has the computer move until it's the human's turn,
+// or the game is over, and gives the computer's moves. If ctx gets
+// cancelled, the client having gone away, it's still the computer's
+// turn, with ErrSearchCancelled.
+func (g *servedGame) computerMoves(ctx context.Context) ([]int, error) {
+	var moves []int
+	for !g.over && g.toMove == kalah.MAXIMIZER {
+		before := time.Now()
+		pit, value, err := g.chooser(ctx, g.bd, false)
+		if err == kalah.ErrSearchCancelled {
+			return moves, err // not the watchers' concern, it'll search again
+		}
+		if err != nil {
+			g.publish(gameEvent{Event: "error", gameState: g.state(nil), Error: err.Error()})
+			return moves, err
+		}
+		g.makeMove(pit, value, time.Since(before))
+		moves = append(moves, pit)
+	}
+	return moves, nil
+}
+
+// makeMove plays pit for whoever's turn it is, keeps the history,
+// and tells the watchers. elapsed is how long the computer took.
+func (g *servedGame) makeMove(pit int, value int, elapsed time.Duration) {
+	player := g.toMove
+	next, _, err := kalah.MakeMove(&g.bd, pit, player)
+	if err != nil {
+		panic(err) // callers check the pit first
+	}
+	g.toMove = next
+	g.over, g.winner = kalah.CheckEnd(&g.bd)
+	after := g.bd
+	after.SetPlayer(-next)
+	g.history = append(g.history, servedMove{
+		Player: playerName(player),
+		Pit:    pit,
+		Value:  value,
+		Board:  after.FEN(),
+	})
+	ev := gameEvent{Event: "move", gameState: g.state(nil), Value: value, ElapsedMS: elapsed.Milliseconds()}
+	if g.over {
+		ev.Event = "game_over"
+	}
+	g.publish(ev)
+}
+
+// publish sends ev to every watcher. A watcher that has fallen too
+// far behind gets its channel closed instead, which hangs it up.
+func (g *servedGame) publish(ev gameEvent) {
+	g.watchMu.Lock()
+	defer g.watchMu.Unlock()
+	g.lastEvent = ev
+	for ch := range g.watchers {
+		select {
+		case ch <- ev:
+		default:
+			delete(g.watchers, ch)
+			close(ch)
+		}
+	}
+}
+
+// subscribe gives a new watcher's channel, with the latest event
+// already in it.
+func (g *servedGame) subscribe() chan gameEvent {
+	g.watchMu.Lock()
+	defer g.watchMu.Unlock()
+	ch := make(chan gameEvent, watcherBuffer)
+	ch <- g.lastEvent
+	g.watchers[ch] = true
+	return ch
+}
+
+func (g *servedGame) unsubscribe(ch chan gameEvent) {
+	g.watchMu.Lock()
+	defer g.watchMu.Unlock()
+	delete(g.watchers, ch)
+}
+
+// watch streams a game's events over a WebSocket, starting with the
+// game as it is, until the game ends, or the client goes away. One
+// goroutine reads from the client, and cancels ctx when the client
+// closes or the connection breaks, so the goroutine writing events
+// doesn't wait on a game that may never move again.
+func (s *gameServer) watch(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/ws/game/")
+	g := s.game(id)
+	if g == nil {
+		httpError(w, http.StatusNotFound, "no game %q", id)
+		return
+	}
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	defer ws.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		defer cancel()
+		ws.readUntilClosed()
+	}()
+
+	events := g.subscribe()
+	defer g.unsubscribe(events)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return // fell behind
+			}
+			buf, err := json.Marshal(ev)
+			if err != nil {
+				log.Print(err)
+				return
+			}
+			if err := ws.writeText(buf); err != nil || ev.Event == "game_over" {
+				return
+			}
+		}
+	}
+}
+
+func (g *servedGame) state(moves []int) gameState {
+	bd := g.bd
+	bd.SetPlayer(-g.toMove) // so the FEN has the right player to move
+	st := gameState{ID: g.id, Board: bd, FEN: bd.FEN(), Over: g.over, Moves: moves}
+	switch {
+	case !g.over:
+		st.ToMove = playerName(g.toMove)
+	case g.winner == kalah.UNSET:
+		st.Winner = "draw"
+	default:
+		st.Winner = playerName(g.winner)
+	}
+	return st
+}
+
+func playerName(player int) string {
+	if player == kalah.MAXIMIZER {
+		return "computer"
+	}
+	return "human"
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Print(err)
+	}
+}
+
+func httpError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf(format, args...)})
+}
+
+// Timeouts keep one slow client from holding a connection open
+// forever. Searches can take a while, so writes get longer.
+const (
+	servedReadTimeout  = 30 * time.Second
+	servedWriteTimeout = 10 * time.Minute
+)
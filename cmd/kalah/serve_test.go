@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"kalah"
+)
+
+// request has s handle method path with body, on ctx, and gives the
+// status and the JSON that came back.
+func request(t *testing.T, s *gameServer, ctx context.Context, method, path, body string) (int, map[string]interface{}) {
+	t.Helper()
+	r := httptest.NewRequest(method, path, strings.NewReader(body)).WithContext(ctx)
+	w := httptest.NewRecorder()
+	if path == "/game" {
+		s.newGame(w, r)
+	} else {
+		s.route(w, r)
+	}
+	var reply map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("%s %s: %v, %q", method, path, err, w.Body.String())
+	}
+	return w.Code, reply
+}
+
+func newTestServer() *gameServer {
+	return &gameServer{opts: []kalah.Option{kalah.WithDepth(2)}, games: make(map[string]*servedGame)}
+}
+
+// TestServedGameEviction checks that games go away once they've
+// gone unasked about too long, sooner once they're over, and that
+// their watchers get hung up on.
+func TestServedGameEviction(t *testing.T) {
+	s := newTestServer()
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if code, reply := request(t, s, ctx, http.MethodPost, "/game", ""); code != http.StatusCreated {
+			t.Fatalf("new game: %d %v", code, reply)
+		}
+	}
+	g1, g2 := s.game("1"), s.game("2")
+	if left := time.Until(g1.expires); left < servedGameTTL-time.Minute || left > servedGameTTL {
+		t.Errorf("a new game expires in %v, want %v", left, servedGameTTL)
+	}
+	watcher := g1.subscribe()
+	<-watcher // the game as it is
+
+	// game 1 idle too long, gone when asked about
+	s.mu.Lock()
+	g1.expires = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+	if code, _ := request(t, s, ctx, http.MethodGet, "/game/1", ""); code != http.StatusNotFound {
+		t.Errorf("idle game: %d, want %d", code, http.StatusNotFound)
+	}
+	if _, ok := <-watcher; ok {
+		t.Error("evicted game's watcher still connected")
+	}
+
+	// game 2 idle too long, gone when a new game starts
+	s.mu.Lock()
+	g2.expires = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+	request(t, s, ctx, http.MethodPost, "/game", "")
+	s.mu.Lock()
+	_, found := s.games["2"]
+	left := len(s.games)
+	s.mu.Unlock()
+	if found || left != 2 {
+		t.Errorf("%d games after a new game, game 2 there %v, want 2 without it", left, found)
+	}
+
+	// game 3 over, gone sooner
+	bd, err := kalah.BoardFromFEN("0.0.0.1.0.0/0.0.0.0.0.1 23 23 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g3 := s.game("3")
+	g3.mu.Lock()
+	g3.bd = bd // the human's pit 5 ends it
+	g3.mu.Unlock()
+	code, reply := request(t, s, ctx, http.MethodPost, "/game/3/move", `{"pit": 5}`)
+	if code != http.StatusOK || reply["over"] != true {
+		t.Fatalf("last move: %d %v, want the game over", code, reply)
+	}
+	if left := time.Until(g3.expires); left > servedOverTTL {
+		t.Errorf("finished game expires in %v, want %v", left, servedOverTTL)
+	}
+}
+
+// TestServedGameCancelled checks that the computer's search stops when
+// the client goes away, and starts over with the next request.
+func TestServedGameCancelled(t *testing.T) {
+	s := newTestServer()
+	request(t, s, context.Background(), http.MethodPost, "/game", "")
+	g := s.game("1")
+	g.mu.Lock()
+	chooser := g.chooser
+	g.chooser = func(ctx context.Context, bd kalah.Board, print bool) (int, int, error) {
+		if ctx.Err() != nil {
+			return 0, 0, kalah.ErrSearchCancelled
+		}
+		return chooser(ctx, bd, print)
+	}
+	g.mu.Unlock()
+
+	gone, cancel := context.WithCancel(context.Background())
+	cancel()
+	if code, _ := request(t, s, gone, http.MethodPost, "/game/1/move", `{"pit": 0}`); code != http.StatusInternalServerError {
+		t.Errorf("move, client gone: %d, want %d", code, http.StatusInternalServerError)
+	}
+	if g.toMove != kalah.MAXIMIZER || len(g.history) != 1 {
+		t.Fatalf("%d to move, %d moves, want the computer's turn after the human's move", g.toMove, len(g.history))
+	}
+	code, reply := request(t, s, context.Background(), http.MethodGet, "/game/1", "")
+	if moves, _ := reply["computer_moves"].([]interface{}); code != http.StatusOK || len(moves) == 0 || reply["to_move"] != "human" {
+		t.Errorf("next request: %d %v, want the computer's moves", code, reply)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// wsConn is the server end of a WebSocket (RFC 6455) connection, only
+// as much of it as streaming games takes: text messages out, and
+// reading what the client sends only to answer pings and notice when
+// it goes away.
+type wsConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	mu   sync.Mutex // the reader answers pings while the writer writes
+}
+
+// wsGUID is the fixed string RFC 6455 has the server hash the
+// client's key with, to show it understood the handshake.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsText  = 0x1
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xA
+
+	// wsMaxRead is the most bytes of one client frame wsConn reads.
+	// Clients don't send anything but control frames, which can't
+	// be longer than 125 bytes.
+	wsMaxRead = 125
+
+	wsWriteTimeout = 10 * time.Second
+)
+
+var errNotWebSocket = errors.New("not a WebSocket handshake")
+
+// upgradeWebSocket does the server side of the WebSocket handshake,
+// and takes the connection over from net/http.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" ||
+		!strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		!headerHasToken(r.Header.Get("Connection"), "upgrade") {
+		return nil, errNotWebSocket
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection can't be taken over")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// the http.Server's timeouts are still on the connection
+	conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + wsGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, rw: rw}, nil
+}
+
+// headerHasToken reports whether a comma-separated header value,
+// like "keep-alive, Upgrade", has token in it.
+func headerHasToken(value, token string) bool {
+	for _, t := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(t), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeText sends msg as a single, unfragmented text message.
+func (c *wsConn) writeText(msg []byte) error {
+	return c.writeFrame(wsText, msg)
+}
+
+// writeFrame sends one frame, unmasked, as servers' frames are.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header := []byte{0x80 | opcode} // FIN, no fragments
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	c.rw.Write(header)
+	c.rw.Write(payload)
+	return c.rw.Flush()
+}
+
+// readUntilClosed reads the client's frames, answering pings, until
+// the client closes the connection, goes away, or sends more than
+// control frames should have. It returns io.EOF for a clean close.
+func (c *wsConn) readUntilClosed() error {
+	var header [2]byte
+	for {
+		if _, err := io.ReadFull(c.rw, header[:]); err != nil {
+			return err
+		}
+		opcode := header[0] & 0x0F
+		n := int(header[1] & 0x7F)
+		if header[1]&0x80 == 0 || n > wsMaxRead {
+			return errors.New("unmasked or too long WebSocket frame from client")
+		}
+		var mask [4]byte
+		if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+			return err
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(c.rw, payload); err != nil {
+			return err
+		}
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+		switch opcode {
+		case wsClose:
+			c.writeFrame(wsClose, payload)
+			return io.EOF
+		case wsPing:
+			if err := c.writeFrame(wsPong, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// close says goodbye, normal closure, and hangs up.
+func (c *wsConn) close() {
+	c.writeFrame(wsClose, []byte{0x03, 0xE8}) // 1000
+	c.conn.Close()
+}
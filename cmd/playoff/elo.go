@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// initialRating is the ELO rating of a player no ratings file has seen.
+const initialRating = 1500.0
+
+// ratings maps players' rating keys, like "MCTS i=200000 U=1.414",
+// to their ELO ratings, which accumulate over runs of playoff in
+// a JSON file.
+type ratings map[string]float64
+
+// loadRatings reads fileName. No file yet is no ratings yet.
+func loadRatings(fileName string) (ratings, error) {
+	r := ratings{}
+	buf, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(buf, &r); err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	return r, nil
+}
+
+// save writes r to fileName by way of a temporary file, so an
+// interrupted playoff doesn't leave half a ratings file.
+func (r ratings) save(fileName string) error {
+	buf, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fileName), ".elo")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(buf, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), fileName)
+}
+
+func (r ratings) rating(key string) float64 {
+	if rating, ok := r[key]; ok {
+		return rating
+	}
+	return initialRating
+}
+
+// update changes the ratings of players a and b after a game
+// between them, with the usual ELO formula. score is a's result,
+// 1 for a win, 0.5 for a draw, 0 for a loss, and k is the most
+// a rating can change in one game.
+func (r ratings) update(a, b string, score, k float64) {
+	ra, rb := r.rating(a), r.rating(b)
+	expected := 1 / (1 + math.Pow(10, (rb-ra)/400))
+	r[a] = ra + k*(score-expected)
+	r[b] = rb - k*(score-expected)
+}
+
+// print writes the ratings, best first.
+func (r ratings) print(w io.Writer) {
+	keys := make([]string, 0, len(r))
+	for key := range r {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if r[keys[i]] != r[keys[j]] {
+			return r[keys[i]] > r[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "%7.1f  %s\n", r[key], key)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"time"
+
+	"kalah"
+)
+
+// Match is Games games between the same two players, who take turns
+// going first unless NoSwap. Results has player 1's wins, player 2's
+// wins and the draws, in that order.
+type Match struct {
+	Player1, Player2 *player
+	Games            int
+	Results          [3]int
+
+	Board   kalah.Board // every game starts here, Player1 on top
+	NoSwap  bool        // Player1 goes first, on top, every game
+	Verbose bool        // show every move, pausing for a newline
+
+	// AfterGame, if not nil, hears about each game once it's over:
+	// which one, counting from 0, who went first, and who won,
+	// MAXIMIZER for Player1, MINIMIZER for Player2, UNSET for a draw.
+	AfterGame func(game int, first *player, winner int, elapsed time.Duration)
+}
+
+// Run plays m's games, adding them to m.Results.
+func (m *Match) Run() {
+	for game := 0; game < m.Games; game++ {
+		// MAXIMIZER moves first, so swapping sides swaps who goes first.
+		swapped := !m.NoSwap && game%2 == 1
+		maximizer, minimizer := m.Player1, m.Player2
+		if swapped {
+			maximizer, minimizer = minimizer, maximizer
+		}
+		// Player2 keeps any handicap, on whichever side it's on.
+		// The minimizer sees the board from its own side, as MAXIMIZER.
+		start := m.Board
+		if swapped {
+			start = start.Mirror()
+		}
+		maximizer.newGame(start)
+		minimizer.newGame(start.Mirror())
+
+		gameStart := time.Now()
+		winner := playGame(start, maximizer, minimizer, m.Verbose)
+		if swapped {
+			winner = -winner // MAXIMIZER is Player1 from here on
+		}
+		switch winner {
+		case kalah.MAXIMIZER:
+			m.Results[0]++
+		case kalah.MINIMIZER:
+			m.Results[1]++
+		default:
+			m.Results[2]++
+		}
+		if m.AfterGame != nil {
+			m.AfterGame(game, maximizer, winner, time.Since(gameStart))
+		}
+	}
+}
+
+// WinRate is the fraction of the games played so far that Player1 won.
+func (m *Match) WinRate() float64 {
+	played := m.Results[0] + m.Results[1] + m.Results[2]
+	if played == 0 {
+		return 0
+	}
+	return float64(m.Results[0]) / float64(played)
+}
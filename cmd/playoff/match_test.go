@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"kalah"
+)
+
+// TestMatchRandomVsRandom plays two random players against each other,
+// with fixed seeds. Every game has to count as a win for one or the
+// other, or a draw, and with sides swapping every game, neither
+// should win many more than half.
+func TestMatchRandomVsRandom(t *testing.T) {
+	const games = 1000
+	p1, err := constructPlayer("R", 0, 0, 0, kalah.RandomRollout{}, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := constructPlayer("R", 0, 0, 0, kalah.RandomRollout{}, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Match{Player1: p1, Player2: p2, Games: games, Board: kalah.NewBoard(4)}
+	m.Run()
+
+	wins1, wins2, draws := m.Results[0], m.Results[1], m.Results[2]
+	if wins1+wins2+draws != games {
+		t.Fatalf("%d wins + %d losses + %d draws, want %d games", wins1, wins2, draws, games)
+	}
+	if rate := m.WinRate(); rate < 0.4 || rate > 0.6 {
+		t.Errorf("player 1 won %.1f%% of games, %d to %d, %d draws, want about half",
+			100*rate, wins1, wins2, draws)
+	}
+	if p1.illegal != 0 || p2.illegal != 0 {
+		t.Errorf("illegal moves: %d and %d, want none", p1.illegal, p2.illegal)
+	}
+}
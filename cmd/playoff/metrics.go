@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"kalah"
+)
+
+// metrics counts finished games, for Prometheus to scrape. It writes
+// Prometheus' text format itself, there's not enough of it to need
+// the client library.
+type metrics struct {
+	mu     sync.Mutex // one game's numbers all change at once
+	counts gameCounts
+}
+
+type gameCounts struct {
+	games    int
+	wins     [2]int // player 1's, player 2's
+	draws    int
+	duration float64 // seconds, all games together
+}
+
+// gameOver counts a game that took elapsed, won by winner,
+// MAXIMIZER for player 1, MINIMIZER for player 2, UNSET for a draw.
+func (m *metrics) gameOver(winner int, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := &m.counts
+	c.games++
+	switch winner {
+	case kalah.MAXIMIZER:
+		c.wins[0]++
+	case kalah.MINIMIZER:
+		c.wins[1]++
+	default:
+		c.draws++
+	}
+	c.duration += elapsed.Seconds()
+}
+
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	g := m.counts
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP kalah_games_played_total Games played to the end.\n")
+	fmt.Fprintf(w, "# TYPE kalah_games_played_total counter\n")
+	fmt.Fprintf(w, "kalah_games_played_total %d\n", g.games)
+	fmt.Fprintf(w, "# HELP kalah_wins_total Games won, by player.\n")
+	fmt.Fprintf(w, "# TYPE kalah_wins_total counter\n")
+	fmt.Fprintf(w, "kalah_wins_total{player=\"1\"} %d\n", g.wins[0])
+	fmt.Fprintf(w, "kalah_wins_total{player=\"2\"} %d\n", g.wins[1])
+	fmt.Fprintf(w, "# HELP kalah_draws_total Games drawn.\n")
+	fmt.Fprintf(w, "# TYPE kalah_draws_total counter\n")
+	fmt.Fprintf(w, "kalah_draws_total %d\n", g.draws)
+	fmt.Fprintf(w, "# HELP kalah_game_duration_seconds How long games took.\n")
+	fmt.Fprintf(w, "# TYPE kalah_game_duration_seconds summary\n")
+	fmt.Fprintf(w, "kalah_game_duration_seconds_sum %g\n", g.duration)
+	fmt.Fprintf(w, "kalah_game_duration_seconds_count %d\n", g.games)
+}
+
+// serveMetrics serves m at /metrics on addr, like ":9090",
+// in the background.
+func serveMetrics(addr string, m *metrics) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	go func() {
+		log.Print(http.ListenAndServe(addr, mux))
+	}()
+}
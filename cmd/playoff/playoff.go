@@ -0,0 +1,427 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"kalah"
+)
+
+type player struct {
+	name    string
+	eloKey  string // name, and the settings that make it stronger or weaker
+	bd      kalah.Board
+	moveFn  kalah.ChooserFunction
+	illegal int          // moves it chose that weren't legal
+	clock   *kalah.Clock // nil without -time-control
+	periods []kalah.TimePeriod
+}
+
+// newGame sets p up to start a game on bd, with a new clock.
+func (p *player) newGame(bd kalah.Board) {
+	p.bd = bd
+	if p.clock != nil {
+		*p.clock = *kalah.NewClock(p.periods) // choosers have p.clock too
+	}
+}
+
+func main() {
+
+	player1Type := flag.String("1", "M", "first player type")
+	player2Type := flag.String("2", "A", "second player type")
+	pitsPtr := flag.Int("p", 6, "number of pits per side")
+	noCapturePtr := flag.Bool("no-capture", false, "no captures, last stones in empty pits stay there")
+	avalanchePtr := flag.Bool("avalanche", false, "avalanche rule, sowing goes on from a last stone's non-empty pit")
+	maxDepthPtr := flag.Int("d", 6, "maximum lookahead depth, moves for each side")
+	stoneCountPtr := flag.Int("n", 4, "number of stones per pit")
+	handicapPtr := flag.Int("handicap", 0, "player 2 starts with this many more stones per pit than player 1")
+	iterationPtr := flag.Int("i", 200000, "Number of iterations for MCTS")
+	uctkPtr := flag.Float64("U", 1.414, "UCTK factor, MCTS only")
+	rolloutPtr := flag.String("rollout", "random", "MCTS playout moves: random, greedy, or mixed:P, greedy with probability P")
+	seedPtr := flag.Int64("seed", 0, "random number seed, 0 seeds from the time of day")
+	timeControlPtr := flag.String("time-control", "", "chess-style time control for each player, like \"40/120,20/60\"")
+	metricsPtr := flag.String("metrics", "", "serve Prometheus metrics at /metrics on this address, like :9090")
+	gamesPtr := flag.Int("games", 1, "number of games to play, more than 1 prints only results")
+	swapPtr := flag.Bool("swap", true, "players swap sides, and who goes first, every other game")
+	jsonPtr := flag.Bool("json", false, "print the results as JSON")
+	eloFilePtr := flag.String("elo-file", "kalah_elo.json", "ELO ratings file, updated after every game")
+	eloKPtr := flag.Float64("elo-k", 32, "ELO K-factor, the most a rating changes in one game")
+	playersPtr := flag.String("players", "", "round robin tournament between these players, like \"A:d=4,A:d=6,M:i=10000:U=2\", -games games a pair")
+	showEloPtr := flag.Bool("show-elo", false, "print the ELO ratings and exit, without playing")
+	flag.Parse()
+
+	elo, err := loadRatings(*eloFilePtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *showEloPtr {
+		elo.print(os.Stdout)
+		return
+	}
+
+	if err := kalah.ValidPits(*pitsPtr); err != nil {
+		log.Fatal(err)
+	}
+	if *handicapPtr < 0 {
+		log.Fatalf("-handicap %d, has to be 0 or more", *handicapPtr)
+	}
+	if *gamesPtr < 1 {
+		log.Fatalf("-games %d, has to be at least 1", *gamesPtr)
+	}
+
+	rollout, err := kalah.ParseRollout(*rolloutPtr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var periods []kalah.TimePeriod
+	if *timeControlPtr != "" {
+		if periods, err = kalah.ParseTimeControl(*timeControlPtr); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	seed := *seedPtr
+	if seed == 0 {
+		seed = time.Now().UTC().UnixNano()
+	}
+
+	rules := kalah.Rules{Avalanche: *avalanchePtr, NoCapture: *noCapturePtr}
+	types := [2]string{*player1Type, *player2Type}
+
+	var gameMetrics *metrics
+	if *metricsPtr != "" {
+		gameMetrics = &metrics{}
+		serveMetrics(*metricsPtr, gameMetrics)
+	}
+
+	bd := kalah.NewBoardSides(*pitsPtr, *stoneCountPtr, *stoneCountPtr+*handicapPtr)
+	bd.SetRules(rules)
+
+	if *playersPtr != "" {
+		specs, err := parsePlayers(*playersPtr, *maxDepthPtr, *iterationPtr, *uctkPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		t := &Tournament{GamesPerPair: *gamesPtr, Board: bd}
+		keys := map[string]int{}
+		for i, spec := range specs {
+			p, err := constructPlayer(spec.typ, spec.depth, spec.iterations, spec.uctk, rollout, seed+int64(i+1), periods)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if *timeControlPtr != "" {
+				p.eloKey += " time-control=" + *timeControlPtr
+			}
+			if k, dup := keys[p.eloKey]; dup {
+				log.Fatalf("-players: players %d and %d are both %s", k+1, i+1, p.eloKey)
+			}
+			keys[p.eloKey] = i
+			t.Players = append(t.Players, p)
+		}
+		t.AfterGame = func(i, j int, m *Match, game int, winner int, elapsed time.Duration) {
+			if gameMetrics != nil {
+				gameMetrics.gameOver(winner, elapsed)
+			}
+			elo.update(m.Player1.eloKey, m.Player2.eloKey, float64(1+winner)/2, *eloKPtr)
+			if err := elo.save(*eloFilePtr); err != nil {
+				log.Fatal(err)
+			}
+			if game == m.Games-1 && !*jsonPtr {
+				fmt.Printf("%s against %s: %d wins, %d losses, %d draws\n",
+					m.Player1.eloKey, m.Player2.eloKey, m.Results[0], m.Results[1], m.Results[2])
+			}
+		}
+		t.RunRoundRobin()
+		if *jsonPtr {
+			if err := writeStandingsJSON(os.Stdout, t.Standings()); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+		printStandings(os.Stdout, t.Standings())
+		return
+	}
+
+	// One game shows every move, as it always has. More than that,
+	// or JSON, and it's just the results.
+	verbose := *gamesPtr == 1 && !*jsonPtr
+	if verbose {
+		fmt.Printf("Seed %d\n", seed)
+	}
+	// Random players get different seeds, so they don't play the same moves.
+	var players [2]*player
+	for i := range players {
+		players[i], err = constructPlayer(types[i], *maxDepthPtr, *iterationPtr, *uctkPtr, rollout, seed+int64(i+1), periods)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *timeControlPtr != "" {
+			players[i].eloKey += " time-control=" + *timeControlPtr
+		}
+	}
+	var results standings
+	for i, p := range players {
+		results.names[i] = fmt.Sprintf("player %d (%s)", i+1, p.name)
+	}
+
+	match := &Match{
+		Player1: players[0],
+		Player2: players[1],
+		Games:   *gamesPtr,
+		Board:   bd,
+		NoSwap:  !*swapPtr,
+		Verbose: verbose,
+	}
+	match.AfterGame = func(game int, first *player, winner int, elapsed time.Duration) {
+		if gameMetrics != nil {
+			gameMetrics.gameOver(winner, elapsed)
+		}
+		// After every game, so stopping playoff early loses nothing.
+		elo.update(players[0].eloKey, players[1].eloKey, float64(1+winner)/2, *eloKPtr)
+		if err := elo.save(*eloFilePtr); err != nil {
+			log.Fatal(err)
+		}
+		if !verbose && !*jsonPtr {
+			result := "draw"
+			if winner != kalah.UNSET {
+				result = results.names[(1-winner)/2] + " won"
+			}
+			firstName := results.names[0]
+			if first == players[1] {
+				firstName = results.names[1]
+			}
+			fmt.Printf("Game %d, %s first: %s\n", game+1, firstName, result)
+		}
+	}
+	match.Run()
+	results.wins = [2]int{match.Results[0], match.Results[1]}
+	results.draws = match.Results[2]
+
+	switch {
+	case *jsonPtr:
+		if err := results.writeJSON(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	case *gamesPtr > 1:
+		results.print(os.Stdout)
+	}
+	if !*jsonPtr {
+		for i := range players {
+			fmt.Printf("%s ELO %.1f\n", results.names[i], elo.rating(players[i].eloKey))
+		}
+	}
+}
+
+// playGame plays a game on bd between maximizer, who goes first, and
+// minimizer, and gives the winner, MAXIMIZER, MINIMIZER or UNSET for a
+// draw. If verbose, it shows the board and every move, and waits for
+// a newline before each.
+func playGame(bd kalah.Board, maximizer, minimizer *player, verbose bool) int {
+	player := kalah.MAXIMIZER
+	var gameWinner int
+	say := func(format string, args ...interface{}) {
+		if verbose {
+			fmt.Printf(format, args...)
+		}
+	}
+
+GAMELOOP:
+	for {
+		if verbose {
+			fmt.Printf("%v\n> ", bd)
+			_, err := fmt.Scanf("\n")
+			if err != nil {
+				log.Print(err)
+			}
+		}
+
+		var pit, value int
+		var minNxt, maxNxt int
+		var err error
+
+		switch player {
+		case kalah.MAXIMIZER:
+			before := time.Now()
+			pit, value, err = maximizer.moveFn(context.Background(), maximizer.bd, false)
+			if err != nil && err != kalah.ErrNoMoves {
+				log.Fatalf("%s: %v", maximizer.name, err)
+			}
+			say("%s chooses %d (%d)\n", maximizer.name, pit, value)
+			if maximizer.outOfTime(time.Since(before), verbose) {
+				say("Game over, player 2 won on time\n")
+				gameWinner = kalah.MINIMIZER
+				break GAMELOOP
+			}
+			pit = maximizer.legalize(&bd, kalah.MAXIMIZER, pit)
+			maxNxt, _, _ = kalah.MakeMove(&(maximizer.bd), pit, kalah.MAXIMIZER)
+			minNxt, _, _ = kalah.MakeMove(&(minimizer.bd), pit, kalah.MINIMIZER)
+			if maxNxt != (0 - minNxt) {
+				fmt.Printf("maximizer says %d goes next\n", maxNxt)
+				fmt.Printf("minimizer says %d goes next\n", 0-minNxt)
+			}
+		case kalah.MINIMIZER:
+			before := time.Now()
+			pit, value, err = minimizer.moveFn(context.Background(), minimizer.bd, false)
+			if err != nil && err != kalah.ErrNoMoves {
+				log.Fatalf("%s: %v", minimizer.name, err)
+			}
+			say("%s chooses %d (%d)\n", minimizer.name, pit, value)
+			if minimizer.outOfTime(time.Since(before), verbose) {
+				say("Game over, player 1 won on time\n")
+				gameWinner = kalah.MAXIMIZER
+				break GAMELOOP
+			}
+			pit = minimizer.legalize(&bd, kalah.MINIMIZER, pit)
+			minNxt, _, _ = kalah.MakeMove(&(minimizer.bd), pit, kalah.MAXIMIZER)
+			maxNxt, _, _ = kalah.MakeMove(&(maximizer.bd), pit, kalah.MINIMIZER)
+			if maxNxt != (0 - minNxt) {
+				fmt.Printf("maximizer says %d goes next\n", maxNxt)
+				fmt.Printf("minimizer says %d goes next\n", 0-minNxt)
+			}
+		}
+
+		player, _, err = kalah.MakeMove(&bd, pit, player)
+		if err != nil {
+			log.Fatal(err)
+		}
+		gameEnd, winner := kalah.CheckEnd(&bd)
+		compare3(&bd, &(maximizer.bd), &(minimizer.bd))
+		if player != maxNxt || player != (0-minNxt) {
+			fmt.Printf("referee   says %d goes next\n", player)
+			fmt.Printf("maximizer says %d goes next\n", maxNxt)
+			fmt.Printf("minimizer says %d goes next\n", 0-minNxt)
+		}
+		if gameEnd {
+			w := "player 1"
+			if winner == kalah.MINIMIZER {
+				w = "player 2"
+			}
+			say("Game over, %s won\n", w)
+			gameWinner = winner
+			break GAMELOOP
+		}
+	}
+	say("Final:\n%v\n", bd)
+	maximizer.reportIllegal()
+	minimizer.reportIllegal()
+	return gameWinner
+}
+
+func (p *player) reportIllegal() {
+	if p.illegal > 0 {
+		fmt.Printf("%s chose %d illegal moves\n", p.name, p.illegal)
+	}
+}
+
+// outOfTime takes a move that took elapsed off p's clock, if it has
+// one, and reports whether that ran the clock out. If verbose,
+// it says how much time p has left.
+func (p *player) outOfTime(elapsed time.Duration, verbose bool) bool {
+	if p.clock == nil {
+		return false
+	}
+	p.clock.Spend(elapsed)
+	if !verbose {
+		return p.clock.Expired()
+	}
+	if p.clock.Expired() {
+		fmt.Printf("%s ran out of time\n", p.name)
+		return true
+	}
+	fmt.Printf("%s has %v\n", p.name, p.clock)
+	return false
+}
+
+// legalize checks pit, which p chose, against side's legal moves on
+// the referee's board. A chooser that falls back to pit 0 without
+// looking can choose an empty pit, and one that finds no moves on its
+// own board, which should agree with ref but might not, gives -1 and
+// kalah.ErrNoMoves. For an illegal pit, legalize
+// complains, counts it, and gives the first legal move instead,
+// so the game can go on.
+func (p *player) legalize(ref *kalah.Board, side int, pit int) int {
+	moves := ref.LegalMoves(side)
+	for _, m := range moves {
+		if m == pit {
+			return pit
+		}
+	}
+	p.illegal++
+	fmt.Printf("ERROR: %s chose illegal pit %d, playing %d instead\n", p.name, pit, moves[0])
+	return moves[0]
+}
+
+// playoffPitWeights gives the static value playoff has always used,
+// a little different than kalah's default.
+var playoffPitWeights = [6]float64{1, 1, 1, 1, 1, 2}
+
+// constructPlayer sets up a player of type typ. With time control
+// periods, MCTS, alpha/beta and hybrid players search only as long as
+// their clock allows, MCTS and alpha/beta up to the iterations or depth,
+// the hybrid for all of what the clock gives a move. The other players
+// still have their time counted against them.
+func constructPlayer(typ string, maxDepth int, mctsIterations int, uctk float64, rollout kalah.RolloutPolicy, seed int64, periods []kalah.TimePeriod) (*player, error) {
+	var p player
+
+	if periods != nil {
+		p.clock = kalah.NewClock(periods)
+		p.periods = periods
+	}
+
+	switch typ {
+	case "M": // MCTS+UCB1
+		mcts := kalah.NewMCTS(mctsIterations, uctk)
+		mcts.Clock = p.clock
+		mcts.Rollout = rollout
+		mcts.Seed(seed)
+		p.moveFn = mcts.ChooseMove
+		p.name = "MCTS"
+		p.eloKey = fmt.Sprintf("MCTS i=%d U=%g", mctsIterations, uctk)
+		if _, random := rollout.(kalah.RandomRollout); !random {
+			p.eloKey += " rollout=" + rollout.String()
+		}
+	case "A": // Alpha-beta minimaxing
+		ab := kalah.NewAlphaBeta(maxDepth)
+		ab.PitWeights = playoffPitWeights
+		ab.Clock = p.clock
+		p.moveFn = ab.ChooseMove
+		p.name = "A/B"
+		p.eloKey = fmt.Sprintf("A/B d=%d", maxDepth)
+	case "X": // both, MCTS settles disagreements
+		x := kalah.NewAlphaBetaPlusMCTS(maxDepth, mctsIterations, uctk)
+		x.Clock = p.clock
+		x.Seed(seed)
+		p.moveFn = x.ChooseMove
+		p.name = "A/B+MCTS"
+		p.eloKey = fmt.Sprintf("A/B+MCTS d=%d i=%d U=%g", maxDepth, mctsIterations, uctk)
+	case "R": // uniformly random legal moves
+		r := kalah.NewRandomPlayer(seed)
+		p.moveFn = r.ChooseMove
+		p.name = "Random"
+		p.eloKey = p.name
+	case "G": // most stones in the store this turn
+		p.moveFn = (&kalah.GreedyPlayer{}).ChooseMove
+		p.name = "Greedy"
+		p.eloKey = p.name
+	default:
+		return nil, fmt.Errorf("unknown player type %q", typ)
+	}
+	return &p, nil
+}
+
+// compare3 checks that each player's own copy of the board agrees
+// with the referee's. The minimizer's copy is from its own point of
+// view, it thinks it's MAXIMIZER, so it has to be mirrored first.
+func compare3(ref, max, min *kalah.Board) {
+	if !ref.Equal(*max) || !ref.Equal(min.Mirror()) {
+		fmt.Printf("Boards disagree:\n")
+		fmt.Printf("referee:\n%v\n", ref)
+		fmt.Printf("maximizer:\n%v\n", max)
+		fmt.Printf("minimizer:\n%v\n", min)
+	}
+}
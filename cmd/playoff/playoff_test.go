@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"kalah"
+)
+
+// TestConstructPlayer checks that constructPlayer makes a player for
+// every type, "X" the hybrid among them, that can choose a legal
+// move, and that it gives an error for any other type.
+func TestConstructPlayer(t *testing.T) {
+	tests := []struct {
+		typ, name, eloKey string
+	}{
+		{"M", "MCTS", "MCTS i=200 U=1.414"},
+		{"A", "A/B", "A/B d=3"},
+		{"X", "A/B+MCTS", "A/B+MCTS d=3 i=200 U=1.414"},
+		{"R", "Random", "Random"},
+		{"G", "Greedy", "Greedy"},
+	}
+	bd := kalah.NewBoard(4)
+	for _, tt := range tests {
+		p, err := constructPlayer(tt.typ, 3, 200, 1.414, kalah.RandomRollout{}, 757, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.typ, err)
+			continue
+		}
+		if p.name != tt.name || p.eloKey != tt.eloKey {
+			t.Errorf("%s: name %q, Elo key %q, want %q, %q", tt.typ, p.name, p.eloKey, tt.name, tt.eloKey)
+		}
+		pit, _, err := p.moveFn(context.Background(), bd, false)
+		if err != nil || pit < 0 || pit >= bd.Pits() || bd.Stones(kalah.MAXIMIZER, pit) == 0 {
+			t.Errorf("%s: chose pit %d, %v, want a legal move", tt.typ, pit, err)
+		}
+	}
+
+	for _, typ := range []string{"Q", "", "x", "AB"} {
+		p, err := constructPlayer(typ, 3, 200, 1.414, kalah.RandomRollout{}, 757, nil)
+		if p != nil || err == nil || !strings.Contains(err.Error(), "unknown player type") {
+			t.Errorf("%q: got %v, %v, want an unknown player type error", typ, p, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// standings counts how a series of games between player 1 and
+// player 2 came out.
+type standings struct {
+	names [2]string // "player 1 (MCTS)" and the like
+	wins  [2]int    // player 1's, player 2's
+	draws int
+}
+
+func (s *standings) games() int {
+	return s.wins[0] + s.wins[1] + s.draws
+}
+
+// pValue is the two-sided p-value of an exact binomial test of the
+// games someone won, draws left out, against both players being
+// equally good: the chance of a split at least this lopsided if each
+// won half the time.
+func (s *standings) pValue() float64 {
+	n := s.wins[0] + s.wins[1]
+	if n == 0 {
+		return 1
+	}
+	k := s.wins[0]
+	if s.wins[1] > k {
+		k = s.wins[1]
+	}
+	if 2*k == n {
+		return 1
+	}
+	var tail float64 // P(X >= k), X binomial n, 1/2
+	for i := k; i <= n; i++ {
+		tail += math.Exp(logChoose(n, i) - float64(n)*math.Ln2)
+	}
+	return math.Min(1, 2*tail)
+}
+
+// logChoose is the natural log of n choose k.
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+type playerStats struct {
+	Name       string  `json:"name"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Draws      int     `json:"draws"`
+	WinPercent float64 `json:"win_percent"`
+}
+
+func (s *standings) player(i int) playerStats {
+	ps := playerStats{Name: s.names[i], Wins: s.wins[i], Losses: s.wins[1-i], Draws: s.draws}
+	if g := s.games(); g > 0 {
+		ps.WinPercent = 100 * float64(ps.Wins) / float64(g)
+	}
+	return ps
+}
+
+// print writes s as a table, with the p-value under it.
+func (s *standings) print(w io.Writer) {
+	fmt.Fprintf(w, "%-20s %5s %6s %5s %6s\n", "player", "wins", "losses", "draws", "win %")
+	for i := range s.names {
+		ps := s.player(i)
+		fmt.Fprintf(w, "%-20s %5d %6d %5d %6.1f\n", ps.Name, ps.Wins, ps.Losses, ps.Draws, ps.WinPercent)
+	}
+	fmt.Fprintf(w, "%d games, p-value %.4g against both players being as good\n", s.games(), s.pValue())
+}
+
+// writeJSON writes s as one JSON object.
+func (s *standings) writeJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(struct {
+		Games   int           `json:"games"`
+		Players []playerStats `json:"players"`
+		PValue  float64       `json:"p_value"`
+	}{s.games(), []playerStats{s.player(0), s.player(1)}, s.pValue()})
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"kalah"
+)
+
+// Tournament is a round robin: every one of Players plays a Match of
+// GamesPerPair games against every other, swapping sides, and who goes
+// first, every other game, all of them from Board.
+type Tournament struct {
+	Players      []*player
+	GamesPerPair int
+	Board        kalah.Board
+
+	// AfterGame, if not nil, is every Match's AfterGame, and hears
+	// which match it is too, Players[i] against Players[j].
+	AfterGame func(i, j int, m *Match, game int, winner int, elapsed time.Duration)
+
+	matches [][]Match // RunRoundRobin's results
+}
+
+// Standing is how one of a Tournament's players did in all its games.
+type Standing struct {
+	Player *player `json:"-"`
+	playerStats
+}
+
+// RunRoundRobin plays every pair of t's players against each other,
+// and gives the results: row i, column j is the Match of Players[i],
+// as Player1, against Players[j]. Each pair only plays once, so row j,
+// column i is the same match the other way around, its players and
+// wins swapped. Nobody plays themselves, the diagonal stays empty.
+func (t *Tournament) RunRoundRobin() [][]Match {
+	n := len(t.Players)
+	t.matches = make([][]Match, n)
+	for i := range t.matches {
+		t.matches[i] = make([]Match, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			m := &t.matches[i][j]
+			*m = Match{
+				Player1: t.Players[i],
+				Player2: t.Players[j],
+				Games:   t.GamesPerPair,
+				Board:   t.Board,
+			}
+			if t.AfterGame != nil {
+				i, j := i, j
+				m.AfterGame = func(game int, _ *player, winner int, elapsed time.Duration) {
+					t.AfterGame(i, j, m, game, winner, elapsed)
+				}
+			}
+			m.Run()
+			t.matches[j][i] = Match{
+				Player1: m.Player2,
+				Player2: m.Player1,
+				Games:   m.Games,
+				Results: [3]int{m.Results[1], m.Results[0], m.Results[2]},
+				Board:   m.Board,
+			}
+		}
+	}
+	return t.matches
+}
+
+// Standings gives how every player did in RunRoundRobin's games,
+// best win percentage first. Players with the same win percentage
+// go by fewer losses, then in the order they're in Players.
+func (t *Tournament) Standings() []Standing {
+	standings := make([]Standing, len(t.Players))
+	for i, p := range t.Players {
+		s := Standing{Player: p, playerStats: playerStats{Name: p.eloKey}}
+		for j := range t.matches[i] {
+			r := t.matches[i][j].Results
+			s.Wins += r[0]
+			s.Losses += r[1]
+			s.Draws += r[2]
+		}
+		if g := s.Wins + s.Losses + s.Draws; g > 0 {
+			s.WinPercent = 100 * float64(s.Wins) / float64(g)
+		}
+		standings[i] = s
+	}
+	sort.SliceStable(standings, func(a, b int) bool {
+		if standings[a].WinPercent != standings[b].WinPercent {
+			return standings[a].WinPercent > standings[b].WinPercent
+		}
+		return standings[a].Losses < standings[b].Losses
+	})
+	return standings
+}
+
+// printStandings writes standings as a table, like standings.print.
+func printStandings(w io.Writer, standings []Standing) {
+	width := len("player")
+	for _, s := range standings {
+		if len(s.Name) > width {
+			width = len(s.Name)
+		}
+	}
+	fmt.Fprintf(w, "%-*s %5s %6s %5s %6s\n", width, "player", "wins", "losses", "draws", "win %")
+	for _, s := range standings {
+		fmt.Fprintf(w, "%-*s %5d %6d %5d %6.1f\n", width, s.Name, s.Wins, s.Losses, s.Draws, s.WinPercent)
+	}
+}
+
+// writeStandingsJSON writes standings as one JSON array.
+func writeStandingsJSON(w io.Writer, standings []Standing) error {
+	return json.NewEncoder(w).Encode(standings)
+}
+
+// playerSpec is one of -players' comma separated players: a player
+// type, like -1 and -2 take, then any of its settings, d, i and U, that
+// differ from the flags', each after a colon, like "A:d=4" or
+// "M:i=10000:U=2".
+type playerSpec struct {
+	typ        string
+	depth      int
+	iterations int
+	uctk       float64
+}
+
+// parsePlayers splits -players' list up, the flags' depth, iterations
+// and uctk going to every player that doesn't give its own.
+func parsePlayers(list string, depth, iterations int, uctk float64) ([]playerSpec, error) {
+	var specs []playerSpec
+	for _, field := range strings.Split(list, ",") {
+		parts := strings.Split(strings.TrimSpace(field), ":")
+		spec := playerSpec{typ: parts[0], depth: depth, iterations: iterations, uctk: uctk}
+		for _, setting := range parts[1:] {
+			eq := strings.Index(setting, "=")
+			if eq < 0 {
+				return nil, fmt.Errorf("player %q: setting %q isn't name=value", field, setting)
+			}
+			name, value := setting[:eq], setting[eq+1:]
+			var err error
+			switch name {
+			case "d":
+				spec.depth, err = strconv.Atoi(value)
+			case "i":
+				spec.iterations, err = strconv.Atoi(value)
+			case "U":
+				spec.uctk, err = strconv.ParseFloat(value, 64)
+			default:
+				return nil, fmt.Errorf("player %q: unknown setting %q, d, i or U", field, name)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("player %q: %v", field, err)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) < 2 {
+		return nil, fmt.Errorf("-players %q: a tournament needs at least 2 players", list)
+	}
+	return specs, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+
+	"kalah"
+)
+
+// randomOpening plays plies random moves from the start of a game,
+// and gives the board if it's MAXIMIZER's turn then, the game not over.
+func randomOpening(rng *rand.Rand, plies int) (kalah.Board, bool) {
+	bd := kalah.NewBoard(4)
+	player := kalah.MAXIMIZER
+	for i := 0; i < plies || player != kalah.MAXIMIZER; i++ {
+		moves := bd.LegalMoves(player)
+		if moves == nil {
+			return bd, false
+		}
+		var err error
+		if player, _, err = kalah.MakeMove(&bd, moves[rng.Intn(len(moves))], player); err != nil {
+			panic(err)
+		}
+		if end, _ := kalah.PeekEnd(bd); end {
+			return bd, false
+		}
+	}
+	bd.SetPlayer(kalah.MINIMIZER)
+	return bd, true
+}
+
+// alphaBetaPlayer is an "A" player with pitWeights in its static value.
+func alphaBetaPlayer(depth int, pitWeights [6]float64) *player {
+	ab := kalah.NewAlphaBeta(depth)
+	ab.PitWeights = pitWeights
+	return &player{name: "A/B", eloKey: "A/B", moveFn: ab.ChooseMove}
+}
+
+// TestPitWeightsSelfPlay has alpha/beta with kalah's DefaultPitWeights
+// play alpha/beta with playoff's weights, the pit nearest the store
+// counting double and the rest the same, from random openings, each
+// side playing each opening from both sides. The default weights,
+// stones in pits counting twice as much and more near the store, win
+// about 57% of the games that aren't draws, at depth 2 and depth 3.
+// They have to win at least 54%, about two standard deviations over
+// an even split in 600 games.
+func TestPitWeightsSelfPlay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("self-play takes a while")
+	}
+	const openings, depth = 300, 2
+	rng := rand.New(rand.NewSource(758))
+	weighted := alphaBetaPlayer(depth, kalah.DefaultPitWeights)
+	simple := alphaBetaPlayer(depth, playoffPitWeights)
+	var results [3]int
+	for n := 0; n < openings; {
+		bd, ok := randomOpening(rng, 2+rng.Intn(4))
+		if !ok {
+			continue
+		}
+		n++
+		m := &Match{Player1: weighted, Player2: simple, Games: 2, Board: bd}
+		m.Run()
+		for i := range results {
+			results[i] += m.Results[i]
+		}
+	}
+	t.Logf("weighted %d, simple %d, %d draws", results[0], results[1], results[2])
+	if decided := results[0] + results[1]; 100*results[0] < 54*decided {
+		t.Errorf("weighted pits won %d games, simple %d, %d draws, want at least 54%% of %d",
+			results[0], results[1], results[2], decided)
+	}
+}
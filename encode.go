@@ -0,0 +1,42 @@
+package kalah
+
+import (
+	"fmt"
+	"math"
+)
+
+// EncodedBoard is a board's stones in a byte each: MAXIMIZER's pits,
+// then store, then MINIMIZER's, MaxPits+1 of each, the pits a board
+// doesn't have 0. That's 18 bytes, where Board's two arrays of int
+// are 144.
+type EncodedBoard [2 * (MaxPits + 1)]int8
+
+// MaxEncodedStones is the most stones one pit or store
+// can have for Encode to work.
+const MaxEncodedStones = math.MaxInt8
+
+// Encode gives p's stones as an EncodedBoard. Traditional games, 6 pits
+// of 4 stones, have 48 stones in all. It panics if any pit or store
+// has more than MaxEncodedStones.
+func (p Board) Encode() (e EncodedBoard) {
+	const side = MaxPits + 1
+	for i := 0; i <= p.pits; i++ {
+		if p.maxpits[i] > MaxEncodedStones || p.minpits[i] > MaxEncodedStones {
+			panic(fmt.Sprintf("can't encode board with more than %d stones in a pit or store", MaxEncodedStones))
+		}
+		e[i] = int8(p.maxpits[i])
+		e[side+i] = int8(p.minpits[i])
+	}
+	return e
+}
+
+// Decode sets p's stones from e, which has to be from a board with
+// as many pits as p. Everything else about p, the rules, who moved
+// last, stays the way it was.
+func (p *Board) Decode(e EncodedBoard) {
+	const side = MaxPits + 1
+	for i := 0; i < side; i++ {
+		p.maxpits[i] = int(e[i])
+		p.minpits[i] = int(e[side+i])
+	}
+}
@@ -0,0 +1,177 @@
+package kalah
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PositionalWeights weigh the positional parts of the static value, each
+// MAXIMIZER's count less MINIMIZER's, on top of the store difference
+// and the weighted stones in MAXIMIZER's pits that the plain static
+// value has. A zero weight leaves its part out.
+type PositionalWeights struct {
+	Mobility       float64 // legal moves
+	BonusMoves     float64 // pits whose stones reach exactly the store, for another move
+	CaptureThreats float64 // empty pits with stones across from them
+	FarPit         float64 // stones in pit 0, furthest from the store, a penalty
+	BonusChain     float64 // the longest chain of bonus moves, see Board.BonusMoveChain
+}
+
+// The positional weights DefaultPositionalWeights has.
+const (
+	mobilityWeight      = 1
+	bonusMoveWeight     = 2
+	captureThreatWeight = 1
+	farPitWeight        = 0.5
+	bonusChainWeight    = 1
+)
+
+// DefaultPositionalWeights are the weights Evaluate uses.
+var DefaultPositionalWeights = PositionalWeights{
+	Mobility:       mobilityWeight,
+	BonusMoves:     bonusMoveWeight,
+	CaptureThreats: captureThreatWeight,
+	FarPit:         farPitWeight,
+	BonusChain:     bonusChainWeight,
+}
+
+// Evaluate is the static value of bd at ply, from MAXIMIZER's side,
+// with DefaultPitWeights and w: what alpha/beta gives a position
+// past its search horizon, with Positional set to w.
+func Evaluate(bd *Board, ply int, w PositionalWeights) int {
+	return staticValue(bd, ply, &DefaultPitWeights) + w.positional(bd)
+}
+
+// ParsePositionalWeights turns a comma-separated list of 5 numbers,
+// like "1,2,1,0.5,1", into mobility, bonus move, capture threat,
+// far pit and bonus chain weights, in that order. With only the
+// first 4, from before there was a bonus chain weight, it's 0.
+func ParsePositionalWeights(str string) (PositionalWeights, error) {
+	var w PositionalWeights
+	fields := strings.Split(str, ",")
+	weights := w.components()
+	if len(fields) != len(weights) && len(fields) != len(weights)-1 {
+		return w, fmt.Errorf("want %d positional weights, have %d in %q", len(weights), len(fields), str)
+	}
+	for i, f := range fields {
+		var err error
+		*weights[i], err = strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return w, fmt.Errorf("positional weight %d: %v", i, err)
+		}
+	}
+	return w, nil
+}
+
+// String gives w the way ParsePositionalWeights takes it.
+func (w PositionalWeights) String() string {
+	var fields []string
+	for _, c := range w.components() {
+		fields = append(fields, strconv.FormatFloat(*c, 'g', -1, 64))
+	}
+	return strings.Join(fields, ",")
+}
+
+// components points to w's weights, in ParsePositionalWeights' order.
+func (w *PositionalWeights) components() []*float64 {
+	return []*float64{&w.Mobility, &w.BonusMoves, &w.CaptureThreats, &w.FarPit, &w.BonusChain}
+}
+
+// staticValue is the difference between the stores less ply depth,
+// so that all things equal, the shortest path to a win gets chosen,
+// plus some empirical amount of the stones in MAXIMIZER's pits,
+// weighted by pitWeights.
+func staticValue(bd *Board, ply int, pitWeights *[6]float64) int {
+	var seeds float64
+	n := bd.pits
+	offset := n - len(pitWeights) // pit that gets pitWeights[0]
+	for i := 0; i < offset; i++ {
+		seeds += float64(bd.maxpits[i])
+	}
+	for i, w := range pitWeights {
+		if pit := offset + i; pit >= 0 {
+			seeds += w * float64(bd.maxpits[pit])
+		}
+	}
+	return (bd.maxpits[n] - bd.minpits[n]) - ply + int(seeds/3)
+}
+
+// positional is w's part of bd's static value.
+func (w *PositionalWeights) positional(bd *Board) int {
+	n := bd.pits
+	bonus := 0
+	for i := 0; i < n; i++ {
+		// n-i stones in pit i sow the last one into the store
+		if bd.maxpits[i] == n-i {
+			bonus++
+		}
+		if bd.minpits[i] == n-i {
+			bonus--
+		}
+	}
+	mobility := bd.MobilityDifference()
+	threats := bd.CaptureThreats(MAXIMIZER) - bd.CaptureThreats(MINIMIZER)
+	far := bd.maxpits[0] - bd.minpits[0]
+	value := w.Mobility*float64(mobility) +
+		w.BonusMoves*float64(bonus) +
+		w.CaptureThreats*float64(threats) -
+		w.FarPit*float64(far)
+	if w.BonusChain != 0 { // it takes making moves
+		chain := longestChain(bd, MAXIMIZER) - longestChain(bd, MINIMIZER)
+		value += w.BonusChain * float64(chain)
+	}
+	return int(value)
+}
+
+// longestChain is the longest BonusMoveChain player has on bd.
+func longestChain(bd *Board, player int) int {
+	longest := 0
+	for pit := 0; pit < bd.pits; pit++ {
+		// earnsBonus is quicker, but doesn't know about avalanches
+		if bd.rules.Avalanche || bd.earnsBonus(player, pit) {
+			if chain := bd.BonusMoveChain(pit, player); chain > longest {
+				longest = chain
+			}
+		}
+	}
+	return longest
+}
+
+// TuneWeights starts each weight's changes at tuneStep, and stops
+// once halving it gets below tuneMinStep. Scores from a few games are
+// noisy, and can keep looking a little better by chance, so at most
+// tuneMaxRounds passes over the weights go by with each step.
+const (
+	tuneStep      = 1.0
+	tuneMinStep   = 0.125
+	tuneMaxRounds = 10
+)
+
+// TuneWeights hill-climbs from DefaultPositionalWeights to weights that
+// matchFn scores higher. matchFn should play some games with the
+// weights it gets, and give something like a win rate against a fixed
+// opponent. TuneWeights tries each weight a step up and a step down,
+// keeps any change that scores better, and halves the step once none
+// does. That's a lot of calls to matchFn, each as slow as its games.
+func TuneWeights(matchFn func(PositionalWeights) float64) PositionalWeights {
+	best := DefaultPositionalWeights
+	bestScore := matchFn(best)
+	for step := tuneStep; step >= tuneMinStep; step /= 2 {
+		improved := true
+		for round := 0; improved && round < tuneMaxRounds; round++ {
+			improved = false
+			for i := range best.components() {
+				for _, delta := range []float64{step, -step} {
+					w := best
+					*w.components()[i] += delta
+					if score := matchFn(w); score > bestScore {
+						best, bestScore = w, score
+						improved = true
+					}
+				}
+			}
+		}
+	}
+	return best
+}
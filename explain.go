@@ -0,0 +1,92 @@
+package kalah
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// abChoice is what ExplainMove needs to know
+// about the last move chooseAlphaBeta chose.
+type abChoice struct {
+	board Board // what MAXIMIZER chose a move for
+	pit   int
+	value int
+	book  bool // from the opening book, not searched
+	ok    bool // false until chooseAlphaBeta has chosen something
+}
+
+// ExplainMove says, in a sentence or three, why the last search chose
+// the move it did on bd: what the move does right away, what the search
+// expects to come of it, and what the search thinks the opponent will
+// play in reply. It's empty if the last search wasn't for bd.
+// The reply is the next move of the search's principal variation,
+// with EnableBestLine on. Without one, or if the line stops short,
+// working out the reply takes another search.
+func (ab *AlphaBeta) ExplainMove(bd Board) string {
+	c := ab.last
+	if !c.ok || !c.board.Equal(bd) {
+		return ""
+	}
+	if c.book {
+		return fmt.Sprintf("I chose pit %d because it's in my opening book.", c.pit)
+	}
+
+	after := bd.Clone()
+	next, _, err := MakeMove(&after, c.pit, MAXIMIZER)
+	if err != nil {
+		panic(err) // chooseAlphaBeta only chooses legal moves
+	}
+	end, winner := CheckEnd(&after)
+
+	var because string
+	captured := bd.CapturePreview(MAXIMIZER, c.pit)
+	switch {
+	case end && winner == MAXIMIZER:
+		because = "it wins the game"
+	case end:
+		because = "it ends the game"
+	case captured > 0:
+		because = fmt.Sprintf("it captures %d stones", captured)
+		if lead := after.Store(MAXIMIZER) - after.Store(MINIMIZER); lead > 0 {
+			because += fmt.Sprintf(" (gaining a %d-stone advantage)", lead)
+		}
+	case next == MAXIMIZER:
+		because = "its last stone lands in my store, so I move again"
+	default:
+		because = fmt.Sprintf("it looks best %d moves ahead", ab.maxPly/2)
+	}
+	sentences := []string{fmt.Sprintf("I chose pit %d because %s.", c.pit, because)}
+	if end {
+		return sentences[0]
+	}
+
+	switch {
+	case c.value >= WIN/2:
+		sentences = append(sentences, "I expect to win from here.")
+	case c.value <= LOSS/2:
+		sentences = append(sentences, "I expect to lose whatever I play.")
+	}
+
+	if next == MINIMIZER {
+		if pit, ok := ab.expectedReply(c, after); ok {
+			sentences = append(sentences, fmt.Sprintf("I expect you to reply with pit %d.", pit))
+		}
+	}
+	return strings.Join(sentences, " ")
+}
+
+// expectedReply is the opponent's move the search that chose c
+// expects, after is the board after c's move. It's the principal
+// variation's second move, if there is one, or else a search from the
+// opponent's side with the same depth and evaluation.
+func (ab *AlphaBeta) expectedReply(c abChoice, after Board) (int, bool) {
+	if line := ab.line; line != nil && len(line.best) > 1 && line.best[0] == c.pit {
+		return line.best[1], true
+	}
+	reply := NewAlphaBeta(ab.maxPly / 2)
+	reply.PV, reply.NullMove, reply.Futility, reply.Negamax = ab.PV, ab.NullMove, ab.Futility, ab.Negamax
+	reply.PitWeights, reply.Positional, reply.TT = ab.PitWeights, ab.Positional, ab.TT
+	pit, _, err := reply.chooseAlphaBeta(context.Background(), after.Mirror(), false)
+	return pit, err == nil
+}
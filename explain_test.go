@@ -0,0 +1,65 @@
+package kalah
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// TestExplainMoveReply checks that the reply ExplainMove expects is
+// the one in the search's principal variation, positional weights and
+// all, and that without a line, with MTD(f), it's still a legal move.
+func TestExplainMoveReply(t *testing.T) {
+	rng := rand.New(rand.NewSource(779))
+	tried := 0
+	for tried < 30 {
+		bd, ok := randomPosition(rng, 6, 4, rng.Intn(20))
+		if !ok {
+			continue
+		}
+		ab := NewAlphaBeta(2)
+		weights := DefaultPositionalWeights
+		ab.Positional = &weights
+		ab.EnableBestLine(true)
+		pit, _, err := ab.ChooseMove(context.Background(), bd, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after := bd.Clone()
+		if next, _, _ := MakeMove(&after, pit, MAXIMIZER); next != MINIMIZER || after.IsTerminal() {
+			continue
+		}
+		line := ab.BestLine()
+		if len(line) < 2 {
+			continue
+		}
+		tried++
+		want := fmt.Sprintf("I expect you to reply with pit %d.", line[1])
+		if explanation := ab.ExplainMove(bd); !strings.HasSuffix(explanation, want) {
+			t.Errorf("%s: line %v, ExplainMove says %q, want it to end %q", bd.FEN(), line, explanation, want)
+		}
+
+		mtdf := NewAlphaBeta(2)
+		mtdf.MTDF = true
+		mtdf.EnableBestLine(true)
+		if pit, _, err = mtdf.ChooseMove(context.Background(), bd, false); err != nil {
+			t.Fatal(err)
+		}
+		after = bd.Clone()
+		if next, _, _ := MakeMove(&after, pit, MAXIMIZER); next != MINIMIZER || after.IsTerminal() {
+			continue
+		}
+		explanation := mtdf.ExplainMove(bd)
+		var reply int
+		i := strings.Index(explanation, "reply with pit ")
+		if i < 0 {
+			t.Errorf("%s: MTD(f) ExplainMove says %q, no reply", bd.FEN(), explanation)
+			continue
+		}
+		if _, err := fmt.Sscanf(explanation[i:], "reply with pit %d.", &reply); err != nil || after.Stones(MINIMIZER, reply) == 0 {
+			t.Errorf("%s: MTD(f) ExplainMove says %q, want a legal reply", bd.FEN(), explanation)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package kalah
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Config holds everything needed to set up a game.
+// NewGame fills one in from its Option arguments.
+type Config struct {
+	Depth        int  // alpha/beta lookahead, moves for each side
+	MCTS         bool // computer uses MCTS instead of alpha/beta
+	Iterations   int  // MCTS iterations per move
+	UCTK         float64
+	RAVE         bool // MCTS blends in Rapid Action Value Estimation
+	PUCT         bool // MCTS selects by PUCT, with GreedyPrior
+	StonesPerPit int
+	Handicap     int // extra stones per pit for MINIMIZER, the human
+	Pits         int // pits per side, 1 to MaxPits
+	Rules        Rules
+	Seed         int64 // 0 means seed from the time of day
+
+	Rollout RolloutPolicy // MCTS playouts' moves, nil for RandomRollout
+
+	IterationTime time.Duration // MCTS iterates this long per move instead of Iterations, 0 for Iterations
+
+	PV         bool       // Principal Variation Search instead of plain alpha/beta
+	NullMove   bool       // null-move pruning in plain alpha/beta
+	Futility   bool       // futility pruning in plain alpha/beta, on by default
+	MTDF       bool       // MTD(f) instead of alpha/beta's full window
+	Negamax    bool       // negamax formulation of plain alpha/beta
+	TT         bool       // transposition table for plain alpha/beta and MTD(f)
+	Aspiration int        // iterative deepening aspiration window delta, 0 for none
+	PitWeights [6]float64 // alpha/beta static value weights
+	Book       OpeningBook
+	Verbose    bool
+
+	Positional *PositionalWeights // alpha/beta positional static value weights, nil for none
+
+	TreeFile      string // non-empty to write alpha/beta game trees as DOT
+	TreeThreshold int
+}
+
+// Rules picks a variant of Kalah. The zero value is Wikipedia's
+// rules: captures, bonus moves, and the game ends when either side
+// runs out of stones.
+type Rules struct {
+	// Avalanche: a last stone that lands in a non-empty pit on the
+	// mover's side gets that pit's stones picked up and sown, too.
+	Avalanche bool
+	// NoCapture: a last stone in an empty pit doesn't capture anything.
+	NoCapture bool
+}
+
+// Option is a functional option for NewGame.
+type Option func(*Config)
+
+// WithDepth sets alpha/beta lookahead, in moves for each side.
+func WithDepth(d int) Option {
+	return func(c *Config) { c.Depth = d }
+}
+
+// WithMCTS has the computer use Monte Carlo Tree Search
+// instead of alpha/beta minimaxing.
+func WithMCTS(iterations int, uctk float64) Option {
+	return func(c *Config) {
+		c.MCTS = true
+		c.Iterations = iterations
+		c.UCTK = uctk
+	}
+}
+
+// WithIterationTime has MCTS do as many iterations as it can
+// in d each move, instead of a fixed number.
+func WithIterationTime(d time.Duration) Option {
+	return func(c *Config) { c.IterationTime = d }
+}
+
+// WithRAVE has MCTS use Rapid Action Value Estimation.
+func WithRAVE() Option {
+	return func(c *Config) { c.RAVE = true }
+}
+
+// WithPUCT has MCTS select children by PUCT instead of
+// UCB1, with prior probabilities from GreedyPrior.
+func WithPUCT() Option {
+	return func(c *Config) { c.PUCT = true }
+}
+
+// WithRollout has MCTS playouts choose their moves by r.
+func WithRollout(r RolloutPolicy) Option {
+	return func(c *Config) { c.Rollout = r }
+}
+
+// WithStonesPerPit sets how many stones each pit starts with.
+func WithStonesPerPit(n int) Option {
+	return func(c *Config) { c.StonesPerPit = n }
+}
+
+// WithHandicap gives MINIMIZER, the human, n more stones per pit
+// than the computer has to start with.
+func WithHandicap(n int) Option {
+	return func(c *Config) { c.Handicap = n }
+}
+
+// WithPits sets how many pits each side has, 6 unless
+// this says otherwise. It has to be 1 through MaxPits.
+func WithPits(n int) Option {
+	return func(c *Config) { c.Pits = n }
+}
+
+// WithRules picks a variant of the game.
+func WithRules(r Rules) Option {
+	return func(c *Config) { c.Rules = r }
+}
+
+// WithSeed seeds MCTS's random number generator, for reproducible MCTS.
+func WithSeed(s int64) Option {
+	return func(c *Config) { c.Seed = s }
+}
+
+// WithPVSearch has alpha/beta use Principal Variation Search.
+func WithPVSearch() Option {
+	return func(c *Config) { c.PV = true }
+}
+
+// WithNullMove has plain alpha/beta try null-move pruning.
+func WithNullMove() Option {
+	return func(c *Config) { c.NullMove = true }
+}
+
+// WithTranspositionTable has plain alpha/beta and MTD(f)
+// keep a TranspositionTable of DefaultTTBuckets buckets.
+func WithTranspositionTable() Option {
+	return func(c *Config) { c.TT = true }
+}
+
+// WithFutility turns plain alpha/beta's futility pruning on or off.
+func WithFutility(enabled bool) Option {
+	return func(c *Config) { c.Futility = enabled }
+}
+
+// WithMTDF has the computer search by MTD(f), zero-window
+// alpha/beta searches closing in on the value.
+func WithMTDF() Option {
+	return func(c *Config) { c.MTDF = true }
+}
+
+// WithNegamax has plain alpha/beta search in its negamax form,
+// one loop for both players. The moves and values come out the same.
+func WithNegamax() Option {
+	return func(c *Config) { c.Negamax = true }
+}
+
+// WithAspiration has alpha/beta deepen iteratively, with aspiration
+// windows of delta either side of the previous depth's value.
+func WithAspiration(delta int) Option {
+	return func(c *Config) { c.Aspiration = delta }
+}
+
+// WithPitWeights sets alpha/beta's static value weights
+// of the computer's 6 pits nearest its store.
+func WithPitWeights(w [6]float64) Option {
+	return func(c *Config) { c.PitWeights = w }
+}
+
+// WithPositionalWeights adds mobility, bonus moves, capture threats,
+// a far pit penalty and bonus move chains, weighted by w,
+// to alpha/beta's static value.
+func WithPositionalWeights(w PositionalWeights) Option {
+	return func(c *Config) { c.Positional = &w }
+}
+
+// WithOpeningBook has the computer look up moves in b before searching.
+func WithOpeningBook(b OpeningBook) Option {
+	return func(c *Config) { c.Book = b }
+}
+
+// WithVerbose turns on MCTS debug output and opening book messages.
+func WithVerbose() Option {
+	return func(c *Config) { c.Verbose = true }
+}
+
+// WithGameTreeExport writes each alpha/beta search's game tree
+// to fileName, leaving out nodes with values at or below threshold.
+func WithGameTreeExport(fileName string, threshold int) Option {
+	return func(c *Config) {
+		c.TreeFile = fileName
+		c.TreeThreshold = threshold
+	}
+}
+
+// Game is a board set up for the start of a game,
+// and the computer's move choosing function.
+type Game struct {
+	Config    Config
+	Board     Board
+	Chooser   ChooserFunction
+	AlphaBeta *AlphaBeta // what Chooser uses, nil for MCTS
+	MCTS      *MCTS      // what Chooser uses, nil for alpha/beta
+
+	// OnMove, if not nil, gets called by Play after every move,
+	// with the board after it, set up so FEN() has the right player
+	// to move, who moved, the pit, and the value the mover's
+	// chooser gave it. OnEnd, if not nil, gets called once the game
+	// is over, with the final board and the winner, UNSET for a tie.
+	OnMove func(bd Board, player int, pit int, value int)
+	OnEnd  func(bd Board, winner int)
+}
+
+// NodesEvaluated is how many nodes the computer's last search reached,
+// whichever algorithm it uses.
+func (g *Game) NodesEvaluated() int64 {
+	if g.MCTS != nil {
+		return g.MCTS.NodesEvaluated
+	}
+	if g.AlphaBeta != nil {
+		return g.AlphaBeta.NodesEvaluated
+	}
+	return 0
+}
+
+// BestLine is the line of play the computer's last search expects,
+// its move first, whichever algorithm it uses. Alpha/beta only keeps
+// track of one after AlphaBeta.EnableBestLine(true).
+func (g *Game) BestLine() []int {
+	if g.MCTS != nil {
+		return g.MCTS.BestLine()
+	}
+	if g.AlphaBeta != nil {
+		return g.AlphaBeta.BestLine()
+	}
+	return nil
+}
+
+// NewGame creates a game configured by opts. Without any options,
+// it's the same game as running kalah with no flags.
+func NewGame(opts ...Option) *Game {
+	g := &Game{
+		Config: Config{
+			Depth:        6,
+			Iterations:   200000,
+			UCTK:         1.414,
+			StonesPerPit: 4,
+			Pits:         6,
+			PitWeights:   DefaultPitWeights,
+			Futility:     true,
+		},
+	}
+	for _, opt := range opts {
+		opt(&g.Config)
+	}
+
+	g.Board = NewBoardSides(g.Config.Pits, g.Config.StonesPerPit, g.Config.StonesPerPit+g.Config.Handicap)
+	g.Board.SetRules(g.Config.Rules)
+
+	if g.Config.MCTS {
+		mcts := NewMCTS(g.Config.Iterations, g.Config.UCTK)
+		mcts.Book = g.Config.Book
+		mcts.Verbose = g.Config.Verbose
+		if g.Config.Seed != 0 {
+			mcts.Seed(g.Config.Seed)
+		}
+		if g.Config.RAVE {
+			mcts.EnableRAVE(true, 0)
+		}
+		if g.Config.PUCT {
+			mcts.Formula = "puct"
+			mcts.PriorFn = GreedyPrior
+		}
+		if g.Config.Rollout != nil {
+			mcts.Rollout = g.Config.Rollout
+		}
+		mcts.TimeBudget = g.Config.IterationTime
+		g.Chooser = mcts.ChooseMove
+		g.MCTS = mcts
+		return g
+	}
+
+	ab := NewAlphaBeta(g.Config.Depth)
+	ab.PV = g.Config.PV
+	ab.NullMove = g.Config.NullMove
+	ab.Futility = g.Config.Futility
+	ab.MTDF = g.Config.MTDF
+	ab.Negamax = g.Config.Negamax
+	if g.Config.TT {
+		ab.TT = NewTranspositionTable(0)
+	}
+	if g.Config.Aspiration > 0 {
+		ab.EnableAspiration(true, g.Config.Aspiration)
+	}
+	ab.PitWeights = g.Config.PitWeights
+	ab.Positional = g.Config.Positional
+	ab.Book = g.Config.Book
+	ab.Verbose = g.Config.Verbose
+	if g.Config.TreeFile != "" {
+		ab.ExportGameTree(g.Config.TreeFile, g.Config.TreeThreshold)
+	}
+	g.Chooser = ab.ChooseMove
+	g.AlphaBeta = ab
+
+	return g
+}
+
+// Play plays a game from g.Board, which doesn't change, between the
+// computer, g.Chooser, as MAXIMIZER, and opponent as MINIMIZER, first
+// moving first. Like a playoff, each side has its own copy of the
+// board, from its own point of view, MAXIMIZER to move, so opponent
+// can be another Game's Chooser, or anything else that's a
+// ChooserFunction, a human typing in moves, say. A move that comes with
+// ErrSearchCancelled still gets made. Play stops with ctx's error once
+// ctx gets cancelled, or with a chooser's error, or an illegal move's.
+func (g *Game) Play(ctx context.Context, opponent ChooserFunction, first int) (winner int, err error) {
+	bd := g.Board
+	own, other := bd, bd.Mirror()
+	player := first
+	for move := 1; ; move++ {
+		if err := ctx.Err(); err != nil {
+			return UNSET, err
+		}
+		var pit, value int
+		if player == MAXIMIZER {
+			pit, value, err = g.Chooser(ctx, own, false)
+		} else {
+			pit, value, err = opponent(ctx, other, false)
+		}
+		if err != nil && err != ErrSearchCancelled {
+			return UNSET, fmt.Errorf("move %d: %v", move, err)
+		}
+		mover := player
+		if player, _, err = MakeMove(&bd, pit, mover); err != nil {
+			return UNSET, fmt.Errorf("move %d: %v", move, err)
+		}
+		// each side sees its own moves as MAXIMIZER's
+		MakeMove(&own, pit, mover)
+		MakeMove(&other, pit, -mover)
+		end, w := CheckEnd(&bd)
+		CheckEnd(&own)
+		CheckEnd(&other)
+		if !bd.Equal(own) || !bd.Equal(other.Mirror()) {
+			return UNSET, fmt.Errorf("move %d: boards disagree, MAXIMIZER's:\n%v\nMINIMIZER's:\n%v\nshould be:\n%v", move, own, other.Mirror(), bd)
+		}
+		if g.OnMove != nil {
+			after := bd
+			after.SetPlayer(-player)
+			g.OnMove(after, mover, pit, value)
+		}
+		if end {
+			if g.OnEnd != nil {
+				g.OnEnd(bd, w)
+			}
+			return w, nil
+		}
+	}
+}
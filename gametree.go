@@ -0,0 +1,107 @@
+package kalah
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// exportMaxDepth limits how many plies of the alpha/beta search
+// get recorded by AlphaBeta.ExportGameTree(). A full 12-ply search
+// visits far too many nodes to keep around, let alone render.
+const exportMaxDepth = 4
+
+// abTreeNode is a single move that alphaBeta examined.
+type abTreeNode struct {
+	pit      int
+	player   int
+	value    int
+	cutoff   bool // beta <= alpha after this move
+	children []*abTreeNode
+}
+
+// abTree records the part of the game tree that chooseAlphaBeta
+// explores, so it can be written out as a Graphviz DOT file.
+// All methods work on a nil *abTree, doing nothing.
+type abTree struct {
+	fileName  string
+	threshold int // only write nodes with value above this
+	root      *abTreeNode
+	stack     []*abTreeNode
+	depth     int
+}
+
+func (t *abTree) reset() {
+	if t == nil {
+		return
+	}
+	t.root = &abTreeNode{pit: -1, player: MINIMIZER}
+	t.stack = append(t.stack[:0], t.root)
+	t.depth = 0
+}
+
+// enter gets called just before alphaBeta makes a move. It returns
+// nil when the move is deeper than exportMaxDepth.
+func (t *abTree) enter(pit, player int) *abTreeNode {
+	if t == nil {
+		return nil
+	}
+	t.depth++
+	if t.depth > exportMaxDepth {
+		return nil
+	}
+	n := &abTreeNode{pit: pit, player: player}
+	parent := t.stack[len(t.stack)-1]
+	parent.children = append(parent.children, n)
+	t.stack = append(t.stack, n)
+	return n
+}
+
+// leave gets called with the value of the move that the
+// matching enter() call marked.
+func (t *abTree) leave(n *abTreeNode, value int, cutoff bool) {
+	if t == nil {
+		return
+	}
+	t.depth--
+	if n == nil {
+		return
+	}
+	n.value = value
+	n.cutoff = cutoff
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *abTree) writeDOT() error {
+	f, err := os.Create(t.fileName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(f, "digraph gametree {\n")
+	fmt.Fprintf(f, "\tn0 [label=\"root\\nvalue %d\"];\n", t.root.value)
+	id := 0
+	t.writeChildren(f, t.root, 0, &id)
+	fmt.Fprintf(f, "}\n")
+	return f.Close()
+}
+
+func (t *abTree) writeChildren(w io.Writer, n *abTreeNode, nid int, id *int) {
+	for _, c := range n.children {
+		if c.value <= t.threshold {
+			continue
+		}
+		*id++
+		cid := *id
+		who := "MAX"
+		if c.player == MINIMIZER {
+			who = "MIN"
+		}
+		style := ""
+		if c.cutoff {
+			style = ", style=dashed, color=red"
+		}
+		fmt.Fprintf(w, "\tn%d [label=\"pit %d\\n%s\\nvalue %d\"%s];\n", cid, c.pit, who, c.value, style)
+		fmt.Fprintf(w, "\tn%d -> n%d;\n", nid, cid)
+		t.writeChildren(w, c, cid, id)
+	}
+}
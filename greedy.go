@@ -0,0 +1,50 @@
+package kalah
+
+import "context"
+
+// GreedyPlayer picks the move that puts the most stones in MAXIMIZER's
+// store right away, not looking at what the opponent can do in reply.
+// A move that earns a bonus move also gets the most that the bonus
+// move could add. Ties go to a capturing move, then the lowest pit.
+// Stronger than RandomPlayer, weaker than alpha/beta.
+type GreedyPlayer struct{}
+
+// ChooseMove is a ChooserFunction. The value is
+// how many stones the move gains.
+func (g *GreedyPlayer) ChooseMove(ctx context.Context, bd Board, print bool) (bestpit int, value int, err error) {
+	bestpit, value = -1, -1
+	bestCapture := false
+	for _, pit := range bd.LegalMoves(MAXIMIZER) {
+		gain := greedyGain(bd, pit, true)
+		capture := bd.captures(MAXIMIZER, pit)
+		if gain > value || (gain == value && capture && !bestCapture) {
+			bestpit, value, bestCapture = pit, gain, capture
+		}
+	}
+	if bestpit < 0 {
+		return -1, 0, ErrNoMoves
+	}
+	return bestpit, value, nil
+}
+
+// greedyGain is how many stones MAXIMIZER's store gains by playing pit
+// on bd. If bonus is true, and the move earns a bonus move, the best
+// gain from one more move gets added on.
+func greedyGain(bd Board, pit int, bonus bool) int {
+	before := bd.Store(MAXIMIZER)
+	next, _, err := MakeMove(&bd, pit, MAXIMIZER)
+	if err != nil {
+		panic(err) // only legal moves get tried
+	}
+	gain := bd.Store(MAXIMIZER) - before
+	if bonus && next == MAXIMIZER {
+		best := 0
+		for _, pit2 := range bd.LegalMoves(MAXIMIZER) {
+			if g := greedyGain(bd, pit2, false); g > best {
+				best = g
+			}
+		}
+		gain += best
+	}
+	return gain
+}
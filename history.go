@@ -0,0 +1,46 @@
+package kalah
+
+// GameHistory is the board before every move of a game, most recent
+// last, so that moves can be taken back.
+type GameHistory struct {
+	boards []Board
+}
+
+// Push saves bd, the board just before next makes a move.
+// Push keeps its own copy, the usual MakeMove(&bd, ...) right after
+// doesn't change what's in the history.
+func (h *GameHistory) Push(bd Board, next int) {
+	bd.player = -next // so FEN() and Undo() know who was to move
+	h.boards = append(h.boards, bd)
+}
+
+// Pop takes back the most recent move, returning the board before it.
+func (h *GameHistory) Pop() (Board, bool) {
+	if len(h.boards) == 0 {
+		return Board{}, false
+	}
+	bd := h.boards[len(h.boards)-1]
+	h.boards = h.boards[:len(h.boards)-1]
+	return bd, true
+}
+
+// Undo takes back moves until it gets to the most recent one that
+// player made, returning the board just before that move. For a human
+// against the computer, that's the human's last move and the computer's
+// reply, plus any bonus moves either made. If player hasn't moved yet,
+// Undo leaves the history alone and returns false.
+func (h *GameHistory) Undo(player int) (Board, bool) {
+	for i := len(h.boards) - 1; i >= 0; i-- {
+		if -h.boards[i].player == player {
+			bd := h.boards[i]
+			h.boards = h.boards[:i]
+			return bd, true
+		}
+	}
+	return Board{}, false
+}
+
+// Len is how many moves the history has.
+func (h *GameHistory) Len() int {
+	return len(h.boards)
+}
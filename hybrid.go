@@ -0,0 +1,127 @@
+package kalah
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AlphaBetaPlusMCTS chooses moves with both alpha/beta minimaxing
+// and MCTS. The two searches run at the same time, for half the time
+// a move gets each, or with no time limit, MCTS getting half its
+// iterations. If they agree on a move, that's the move. If they
+// don't, MCTS spends the rest of the time, or the other half of its
+// iterations, on just the two candidate moves, and the one it visits
+// most gets played.
+type AlphaBetaPlusMCTS struct {
+	ab         *AlphaBeta
+	mcts       *MCTS
+	iterations int
+	Verbose    bool
+
+	// TimeBudget, if more than 0, is how long every move takes,
+	// instead of alpha/beta going to its full depth and MCTS doing
+	// its iterations. Alpha/beta deepens iteratively, and gives the
+	// move from the deepest search it finished in its half.
+	TimeBudget time.Duration
+
+	// Clock, if not nil and there's no TimeBudget, gives the time
+	// for each move, its Budget. Whoever runs the game spends the time.
+	Clock *Clock
+}
+
+// NewAlphaBetaPlusMCTS sets up alpha/beta minimaxing depth moves ahead
+// for each side, and MCTS doing a total of iterations playouts per move.
+func NewAlphaBetaPlusMCTS(depth int, iterations int, uctk float64) *AlphaBetaPlusMCTS {
+	return &AlphaBetaPlusMCTS{
+		ab:         NewAlphaBeta(depth),
+		mcts:       NewMCTS(iterations/2, uctk),
+		iterations: iterations,
+	}
+}
+
+// Seed starts the MCTS half's random numbers over from seed.
+func (h *AlphaBetaPlusMCTS) Seed(seed int64) {
+	h.mcts.Seed(seed)
+}
+
+// budget is the time for a move, 0 for going by depth and iterations.
+func (h *AlphaBetaPlusMCTS) budget() time.Duration {
+	if h.TimeBudget > 0 || h.Clock == nil {
+		return h.TimeBudget
+	}
+	return h.Clock.Budget()
+}
+
+// ChooseMove is a ChooserFunction. If ctx gets cancelled, it gives
+// MCTS's move, MCTS's best move so far being a move that it searched.
+func (h *AlphaBetaPlusMCTS) ChooseMove(ctx context.Context, bd Board, print bool) (bestpit int, value int, err error) {
+	var abPit, mctsPit, mctsValue int
+	var abErr, mctsErr error
+
+	start := time.Now()
+	budget := h.budget()
+	h.mcts.TimeBudget = budget / 2
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		abPit, abErr = h.alphaBeta(ctx, bd, budget/2)
+	}()
+	go func() {
+		defer wg.Done()
+		mctsPit, mctsValue, mctsErr = h.mcts.ChooseMove(ctx, bd, false)
+	}()
+	wg.Wait()
+
+	if abErr != nil && abErr != ErrSearchCancelled {
+		return -1, 0, abErr
+	}
+	if mctsErr != nil && mctsErr != ErrSearchCancelled {
+		return -1, 0, mctsErr
+	}
+	if abErr != nil || mctsErr != nil {
+		return mctsPit, mctsValue, ErrSearchCancelled
+	}
+	if abPit == mctsPit {
+		return mctsPit, mctsValue, nil
+	}
+
+	if h.Verbose {
+		fmt.Printf("alpha/beta chooses %d, MCTS chooses %d\n", abPit, mctsPit)
+	}
+	if budget > 0 {
+		// at least one check's worth of iterations, if the first
+		// half ran over
+		h.mcts.TimeBudget = budget - time.Since(start)
+		if h.mcts.TimeBudget <= 0 {
+			h.mcts.TimeBudget = time.Nanosecond
+		}
+	}
+	root, err := h.mcts.search(ctx, bd, []int{abPit, mctsPit}, h.iterations-h.iterations/2)
+	if err != nil && err != ErrSearchCancelled {
+		return -1, 0, err
+	}
+	best := root.mostVisited()
+	return best.move, int(best.wins / float64(best.visits) * 100.), err
+}
+
+// alphaBeta is the alpha/beta half of ChooseMove: ChooseMove, or with
+// a budget more than 0, deepening iteratively until the budget runs
+// out, the best move of the last depth it finished, with nil error.
+func (h *AlphaBetaPlusMCTS) alphaBeta(ctx context.Context, bd Board, budget time.Duration) (int, error) {
+	if budget <= 0 {
+		pit, _, err := h.ab.ChooseMove(ctx, bd, false)
+		return pit, err
+	}
+	timed, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+	h.ab.newSearch()
+	pit, _, err := h.ab.deepen(timed, bd, h.ab.aspirationEnabled)
+	if err == ErrSearchCancelled && ctx.Err() == nil {
+		err = nil // out of time, not cancelled
+	}
+	return pit, err
+}
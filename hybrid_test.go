@@ -0,0 +1,38 @@
+package kalah
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAlphaBetaPlusMCTSTimeBudget gives the hybrid a depth and a number
+// of iterations it couldn't get through in a test, and checks that its
+// TimeBudget stops it about on time anyway, with a legal move.
+func TestAlphaBetaPlusMCTSTimeBudget(t *testing.T) {
+	const budget = 200 * time.Millisecond
+	h := NewAlphaBetaPlusMCTS(30, 1<<30, 1.414)
+	h.Seed(765)
+	h.TimeBudget = budget
+	bd := NewBoard(4)
+	start := time.Now()
+	pit, _, err := h.ChooseMove(context.Background(), bd, false)
+	took := time.Since(start)
+	if err != nil || pit < 0 || pit >= bd.Pits() || bd.Stones(MAXIMIZER, pit) == 0 {
+		t.Fatalf("got pit %d, %v, want a legal move", pit, err)
+	}
+	if took > 2*budget {
+		t.Errorf("took %v, with a %v budget", took, budget)
+	}
+
+	// the Clock's budget, without a TimeBudget
+	h.TimeBudget = 0
+	h.Clock = NewClock([]TimePeriod{{Moves: 20, Seconds: 2}})
+	start = time.Now()
+	if pit, _, err = h.ChooseMove(context.Background(), bd, false); err != nil || bd.Stones(MAXIMIZER, pit) == 0 {
+		t.Fatalf("with a Clock: got pit %d, %v, want a legal move", pit, err)
+	}
+	if took, want := time.Since(start), h.Clock.Budget(); took > 2*want {
+		t.Errorf("with a Clock: took %v, with a %v budget", took, want)
+	}
+}
@@ -0,0 +1,5 @@
+// Package kalahpb has the gRPC service cmd/kalah-server serves,
+// generated from kalah.proto, for servers and clients both.
+package kalahpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative kalah.proto
@@ -0,0 +1,595 @@
+// The KalahEngine service plays kalah against a remote client, the
+// human, as cmd/kalah-server serves it. The computer always plays
+// the side that kalah's library calls MAXIMIZER.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: kalah.proto
+
+package kalahpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Side int32
+
+const (
+	Side_NONE     Side = 0 // nobody: a draw, or no one to move once the game is over
+	Side_COMPUTER Side = 1
+	Side_HUMAN    Side = 2
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "NONE",
+		1: "COMPUTER",
+		2: "HUMAN",
+	}
+	Side_value = map[string]int32{
+		"NONE":     0,
+		"COMPUTER": 1,
+		"HUMAN":    2,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_kalah_proto_enumTypes[0].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_kalah_proto_enumTypes[0]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_kalah_proto_rawDescGZIP(), []int{0}
+}
+
+type NewGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StonesPerPit  int32 `protobuf:"varint,1,opt,name=stones_per_pit,json=stonesPerPit,proto3" json:"stones_per_pit,omitempty"` // 0 for the server's default
+	ComputerFirst bool  `protobuf:"varint,2,opt,name=computer_first,json=computerFirst,proto3" json:"computer_first,omitempty"`
+}
+
+func (x *NewGameRequest) Reset() {
+	*x = NewGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kalah_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewGameRequest) ProtoMessage() {}
+
+func (x *NewGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kalah_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewGameRequest.ProtoReflect.Descriptor instead.
+func (*NewGameRequest) Descriptor() ([]byte, []int) {
+	return file_kalah_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NewGameRequest) GetStonesPerPit() int32 {
+	if x != nil {
+		return x.StonesPerPit
+	}
+	return 0
+}
+
+func (x *NewGameRequest) GetComputerFirst() bool {
+	if x != nil {
+		return x.ComputerFirst
+	}
+	return false
+}
+
+type MakeMoveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId string `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Pit    int32  `protobuf:"varint,2,opt,name=pit,proto3" json:"pit,omitempty"` // 0 through 5, counter-clockwise from the human's left
+}
+
+func (x *MakeMoveRequest) Reset() {
+	*x = MakeMoveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kalah_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MakeMoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeMoveRequest) ProtoMessage() {}
+
+func (x *MakeMoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kalah_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeMoveRequest.ProtoReflect.Descriptor instead.
+func (*MakeMoveRequest) Descriptor() ([]byte, []int) {
+	return file_kalah_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MakeMoveRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *MakeMoveRequest) GetPit() int32 {
+	if x != nil {
+		return x.Pit
+	}
+	return 0
+}
+
+type ComputerMoveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId string `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+}
+
+func (x *ComputerMoveRequest) Reset() {
+	*x = ComputerMoveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kalah_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ComputerMoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputerMoveRequest) ProtoMessage() {}
+
+func (x *ComputerMoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kalah_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputerMoveRequest.ProtoReflect.Descriptor instead.
+func (*ComputerMoveRequest) Descriptor() ([]byte, []int) {
+	return file_kalah_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ComputerMoveRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+// GameState has the board, 14 pit and store values for 6 pits a
+// side, and whose turn it is.
+type GameState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId        string  `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	ComputerPits  []int32 `protobuf:"varint,2,rep,packed,name=computer_pits,json=computerPits,proto3" json:"computer_pits,omitempty"`
+	ComputerStore int32   `protobuf:"varint,3,opt,name=computer_store,json=computerStore,proto3" json:"computer_store,omitempty"`
+	HumanPits     []int32 `protobuf:"varint,4,rep,packed,name=human_pits,json=humanPits,proto3" json:"human_pits,omitempty"`
+	HumanStore    int32   `protobuf:"varint,5,opt,name=human_store,json=humanStore,proto3" json:"human_store,omitempty"`
+	ToMove        Side    `protobuf:"varint,6,opt,name=to_move,json=toMove,proto3,enum=kalah.Side" json:"to_move,omitempty"` // NONE once the game is over
+	Over          bool    `protobuf:"varint,7,opt,name=over,proto3" json:"over,omitempty"`
+	Winner        Side    `protobuf:"varint,8,opt,name=winner,proto3,enum=kalah.Side" json:"winner,omitempty"` // NONE for a draw, or a game that isn't over
+}
+
+func (x *GameState) Reset() {
+	*x = GameState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kalah_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameState) ProtoMessage() {}
+
+func (x *GameState) ProtoReflect() protoreflect.Message {
+	mi := &file_kalah_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameState.ProtoReflect.Descriptor instead.
+func (*GameState) Descriptor() ([]byte, []int) {
+	return file_kalah_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GameState) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *GameState) GetComputerPits() []int32 {
+	if x != nil {
+		return x.ComputerPits
+	}
+	return nil
+}
+
+func (x *GameState) GetComputerStore() int32 {
+	if x != nil {
+		return x.ComputerStore
+	}
+	return 0
+}
+
+func (x *GameState) GetHumanPits() []int32 {
+	if x != nil {
+		return x.HumanPits
+	}
+	return nil
+}
+
+func (x *GameState) GetHumanStore() int32 {
+	if x != nil {
+		return x.HumanStore
+	}
+	return 0
+}
+
+func (x *GameState) GetToMove() Side {
+	if x != nil {
+		return x.ToMove
+	}
+	return Side_NONE
+}
+
+func (x *GameState) GetOver() bool {
+	if x != nil {
+		return x.Over
+	}
+	return false
+}
+
+func (x *GameState) GetWinner() Side {
+	if x != nil {
+		return x.Winner
+	}
+	return Side_NONE
+}
+
+type MoveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pit       int32      `protobuf:"varint,1,opt,name=pit,proto3" json:"pit,omitempty"`
+	Value     int32      `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"` // the computer's value for the move, positive is good for it
+	ElapsedMs int64      `protobuf:"varint,3,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"`
+	State     *GameState `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+}
+
+func (x *MoveResponse) Reset() {
+	*x = MoveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kalah_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveResponse) ProtoMessage() {}
+
+func (x *MoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kalah_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveResponse.ProtoReflect.Descriptor instead.
+func (*MoveResponse) Descriptor() ([]byte, []int) {
+	return file_kalah_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MoveResponse) GetPit() int32 {
+	if x != nil {
+		return x.Pit
+	}
+	return 0
+}
+
+func (x *MoveResponse) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *MoveResponse) GetElapsedMs() int64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+func (x *MoveResponse) GetState() *GameState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+var File_kalah_proto protoreflect.FileDescriptor
+
+var file_kalah_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6b,
+	0x61, 0x6c, 0x61, 0x68, 0x22, 0x5d, 0x0a, 0x0e, 0x4e, 0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x74, 0x6f, 0x6e, 0x65, 0x73,
+	0x5f, 0x70, 0x65, 0x72, 0x5f, 0x70, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c,
+	0x73, 0x74, 0x6f, 0x6e, 0x65, 0x73, 0x50, 0x65, 0x72, 0x50, 0x69, 0x74, 0x12, 0x25, 0x0a, 0x0e,
+	0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x46, 0x69,
+	0x72, 0x73, 0x74, 0x22, 0x3c, 0x0a, 0x0f, 0x4d, 0x61, 0x6b, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x12,
+	0x10, 0x0a, 0x03, 0x70, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69,
+	0x74, 0x22, 0x2e, 0x0a, 0x13, 0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x76,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49,
+	0x64, 0x22, 0x8f, 0x02, 0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6d, 0x70,
+	0x75, 0x74, 0x65, 0x72, 0x5f, 0x70, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52,
+	0x0c, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x50, 0x69, 0x74, 0x73, 0x12, 0x25, 0x0a,
+	0x0e, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x53,
+	0x74, 0x6f, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x75, 0x6d, 0x61, 0x6e, 0x5f, 0x70, 0x69,
+	0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x68, 0x75, 0x6d, 0x61, 0x6e, 0x50,
+	0x69, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x75, 0x6d, 0x61, 0x6e, 0x5f, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x68, 0x75, 0x6d, 0x61, 0x6e, 0x53,
+	0x74, 0x6f, 0x72, 0x65, 0x12, 0x24, 0x0a, 0x07, 0x74, 0x6f, 0x5f, 0x6d, 0x6f, 0x76, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e, 0x53, 0x69,
+	0x64, 0x65, 0x52, 0x06, 0x74, 0x6f, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x76,
+	0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x23,
+	0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b,
+	0x2e, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e, 0x53, 0x69, 0x64, 0x65, 0x52, 0x06, 0x77, 0x69, 0x6e,
+	0x6e, 0x65, 0x72, 0x22, 0x7d, 0x0a, 0x0c, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x03, 0x70, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x65,
+	0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x4d, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6b, 0x61, 0x6c, 0x61,
+	0x68, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x2a, 0x29, 0x0a, 0x04, 0x53, 0x69, 0x64, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x4f,
+	0x4e, 0x45, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x4f, 0x4d, 0x50, 0x55, 0x54, 0x45, 0x52,
+	0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x48, 0x55, 0x4d, 0x41, 0x4e, 0x10, 0x02, 0x32, 0xb8, 0x01,
+	0x0a, 0x0b, 0x4b, 0x61, 0x6c, 0x61, 0x68, 0x45, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x32, 0x0a,
+	0x07, 0x4e, 0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x15, 0x2e, 0x6b, 0x61, 0x6c, 0x61, 0x68,
+	0x2e, 0x4e, 0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x10, 0x2e, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x34, 0x0a, 0x08, 0x4d, 0x61, 0x6b, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x16, 0x2e,
+	0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e, 0x4d, 0x61, 0x6b, 0x65, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e, 0x47, 0x61,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x43, 0x6f, 0x6d, 0x70, 0x75,
+	0x74, 0x65, 0x72, 0x4d, 0x6f, 0x76, 0x65, 0x12, 0x1a, 0x2e, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e,
+	0x43, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x2e, 0x4d, 0x6f, 0x76, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0f, 0x5a, 0x0d, 0x6b, 0x61, 0x6c, 0x61,
+	0x68, 0x2f, 0x6b, 0x61, 0x6c, 0x61, 0x68, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_kalah_proto_rawDescOnce sync.Once
+	file_kalah_proto_rawDescData = file_kalah_proto_rawDesc
+)
+
+func file_kalah_proto_rawDescGZIP() []byte {
+	file_kalah_proto_rawDescOnce.Do(func() {
+		file_kalah_proto_rawDescData = protoimpl.X.CompressGZIP(file_kalah_proto_rawDescData)
+	})
+	return file_kalah_proto_rawDescData
+}
+
+var file_kalah_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_kalah_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_kalah_proto_goTypes = []interface{}{
+	(Side)(0),                   // 0: kalah.Side
+	(*NewGameRequest)(nil),      // 1: kalah.NewGameRequest
+	(*MakeMoveRequest)(nil),     // 2: kalah.MakeMoveRequest
+	(*ComputerMoveRequest)(nil), // 3: kalah.ComputerMoveRequest
+	(*GameState)(nil),           // 4: kalah.GameState
+	(*MoveResponse)(nil),        // 5: kalah.MoveResponse
+}
+var file_kalah_proto_depIdxs = []int32{
+	0, // 0: kalah.GameState.to_move:type_name -> kalah.Side
+	0, // 1: kalah.GameState.winner:type_name -> kalah.Side
+	4, // 2: kalah.MoveResponse.state:type_name -> kalah.GameState
+	1, // 3: kalah.KalahEngine.NewGame:input_type -> kalah.NewGameRequest
+	2, // 4: kalah.KalahEngine.MakeMove:input_type -> kalah.MakeMoveRequest
+	3, // 5: kalah.KalahEngine.ComputerMove:input_type -> kalah.ComputerMoveRequest
+	4, // 6: kalah.KalahEngine.NewGame:output_type -> kalah.GameState
+	4, // 7: kalah.KalahEngine.MakeMove:output_type -> kalah.GameState
+	5, // 8: kalah.KalahEngine.ComputerMove:output_type -> kalah.MoveResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_kalah_proto_init() }
+func file_kalah_proto_init() {
+	if File_kalah_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_kalah_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kalah_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MakeMoveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kalah_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ComputerMoveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kalah_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kalah_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MoveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_kalah_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_kalah_proto_goTypes,
+		DependencyIndexes: file_kalah_proto_depIdxs,
+		EnumInfos:         file_kalah_proto_enumTypes,
+		MessageInfos:      file_kalah_proto_msgTypes,
+	}.Build()
+	File_kalah_proto = out.File
+	file_kalah_proto_rawDesc = nil
+	file_kalah_proto_goTypes = nil
+	file_kalah_proto_depIdxs = nil
+}
@@ -0,0 +1,193 @@
+// The KalahEngine service plays kalah against a remote client, the
+// human, as cmd/kalah-server serves it. The computer always plays
+// the side that kalah's library calls MAXIMIZER.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: kalah.proto
+
+package kalahpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	KalahEngine_NewGame_FullMethodName      = "/kalah.KalahEngine/NewGame"
+	KalahEngine_MakeMove_FullMethodName     = "/kalah.KalahEngine/MakeMove"
+	KalahEngine_ComputerMove_FullMethodName = "/kalah.KalahEngine/ComputerMove"
+)
+
+// KalahEngineClient is the client API for KalahEngine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KalahEngineClient interface {
+	// NewGame starts a game, and gives its ID in the state.
+	NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*GameState, error)
+	// MakeMove plays the human's move.
+	MakeMove(ctx context.Context, in *MakeMoveRequest, opts ...grpc.CallOption) (*GameState, error)
+	// ComputerMove has the computer choose its move, and play it.
+	ComputerMove(ctx context.Context, in *ComputerMoveRequest, opts ...grpc.CallOption) (*MoveResponse, error)
+}
+
+type kalahEngineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKalahEngineClient(cc grpc.ClientConnInterface) KalahEngineClient {
+	return &kalahEngineClient{cc}
+}
+
+func (c *kalahEngineClient) NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*GameState, error) {
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, KalahEngine_NewGame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kalahEngineClient) MakeMove(ctx context.Context, in *MakeMoveRequest, opts ...grpc.CallOption) (*GameState, error) {
+	out := new(GameState)
+	err := c.cc.Invoke(ctx, KalahEngine_MakeMove_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kalahEngineClient) ComputerMove(ctx context.Context, in *ComputerMoveRequest, opts ...grpc.CallOption) (*MoveResponse, error) {
+	out := new(MoveResponse)
+	err := c.cc.Invoke(ctx, KalahEngine_ComputerMove_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KalahEngineServer is the server API for KalahEngine service.
+// All implementations must embed UnimplementedKalahEngineServer
+// for forward compatibility
+type KalahEngineServer interface {
+	// NewGame starts a game, and gives its ID in the state.
+	NewGame(context.Context, *NewGameRequest) (*GameState, error)
+	// MakeMove plays the human's move.
+	MakeMove(context.Context, *MakeMoveRequest) (*GameState, error)
+	// ComputerMove has the computer choose its move, and play it.
+	ComputerMove(context.Context, *ComputerMoveRequest) (*MoveResponse, error)
+	mustEmbedUnimplementedKalahEngineServer()
+}
+
+// UnimplementedKalahEngineServer must be embedded to have forward compatible implementations.
+type UnimplementedKalahEngineServer struct {
+}
+
+func (UnimplementedKalahEngineServer) NewGame(context.Context, *NewGameRequest) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewGame not implemented")
+}
+func (UnimplementedKalahEngineServer) MakeMove(context.Context, *MakeMoveRequest) (*GameState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MakeMove not implemented")
+}
+func (UnimplementedKalahEngineServer) ComputerMove(context.Context, *ComputerMoveRequest) (*MoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComputerMove not implemented")
+}
+func (UnimplementedKalahEngineServer) mustEmbedUnimplementedKalahEngineServer() {}
+
+// UnsafeKalahEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KalahEngineServer will
+// result in compilation errors.
+type UnsafeKalahEngineServer interface {
+	mustEmbedUnimplementedKalahEngineServer()
+}
+
+func RegisterKalahEngineServer(s grpc.ServiceRegistrar, srv KalahEngineServer) {
+	s.RegisterService(&KalahEngine_ServiceDesc, srv)
+}
+
+func _KalahEngine_NewGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KalahEngineServer).NewGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KalahEngine_NewGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KalahEngineServer).NewGame(ctx, req.(*NewGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KalahEngine_MakeMove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MakeMoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KalahEngineServer).MakeMove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KalahEngine_MakeMove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KalahEngineServer).MakeMove(ctx, req.(*MakeMoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KalahEngine_ComputerMove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputerMoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KalahEngineServer).ComputerMove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KalahEngine_ComputerMove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KalahEngineServer).ComputerMove(ctx, req.(*ComputerMoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KalahEngine_ServiceDesc is the grpc.ServiceDesc for KalahEngine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KalahEngine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kalah.KalahEngine",
+	HandlerType: (*KalahEngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NewGame",
+			Handler:    _KalahEngine_NewGame_Handler,
+		},
+		{
+			MethodName: "MakeMove",
+			Handler:    _KalahEngine_MakeMove_Handler,
+		},
+		{
+			MethodName: "ComputerMove",
+			Handler:    _KalahEngine_ComputerMove_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "kalah.proto",
+}
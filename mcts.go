@@ -0,0 +1,699 @@
+package kalah
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MCTS holds values that func chooseMonteCarlo() needs, but
+// aren't passed in as arguments.
+type MCTS struct {
+	moveNode   *Node // root of the most recent search's tree
+	moveBoard  Board // moveNode's board
+	iterations int
+	uctk       float64
+	Book       OpeningBook // nil unless an opening book got loaded
+	Verbose    bool
+
+	// Formula picks how selection scores children: "ucb1", the
+	// default, or "puct", AlphaZero's polynomial UCT, which weights
+	// exploration by each move's prior probability from PriorFn.
+	Formula string
+	PriorFn PriorFn // nil means UniformPrior
+
+	// Rollout picks the moves of every playout, RandomRollout
+	// unless something else gets set, or nil.
+	Rollout RolloutPolicy
+
+	// raveEnabled blends RAVE (Rapid Action Value Estimation) into
+	// selection: how a move did anywhere later in playouts through the
+	// parent, not just right after the parent. raveK is how many
+	// visits it takes for a child's own wins to count as much.
+	raveEnabled bool
+	raveK       float64
+	playout     []raveMove // this iteration's moves below the tree, for RAVE
+	raveLine    []raveMove // backpropagateRAVE's buffer
+
+	// NodesEvaluated is how many iterations, each with a playout,
+	// the last search did, 0 for an opening book move.
+	NodesEvaluated int64
+
+	// Clock, if not nil, has every search stop early, once it's
+	// used up the clock's budget for the move. Whoever runs the
+	// game spends the time.
+	Clock *Clock
+
+	// TimeBudget, if more than 0, has every search do as many
+	// iterations as fit in it, instead of a fixed number.
+	TimeBudget time.Duration
+
+	// arena is where this move's search gets its new nodes.
+	arena *NodeArena
+
+	// rng makes every random choice of this MCTS's searches, the
+	// moves to expand and the playouts' moves, so it doesn't share
+	// random numbers with anything else.
+	rng *rand.Rand
+}
+
+// clockCheckIterations is how many iterations grow does between
+// looking at the clock, time.Now() isn't free.
+const clockCheckIterations = 100
+
+// cancelCheckIterations is how many iterations grow does between
+// looking for its context's cancellation.
+const cancelCheckIterations = 1000
+
+// budgetCheckIterations is how many iterations grow does between
+// looking at the time, with a TimeBudget. It's a power of 2, so the
+// check is a mask, in the loop that takes all of a search's time.
+const budgetCheckIterations = 1024
+
+// PriorFn gives a prior weight for player playing move on bd, for
+// PUCT. Only its size compared to the other legal moves' counts:
+// MCTS divides by the total for all of them.
+type PriorFn func(bd *Board, move int, player int) float64
+
+// UniformPrior gives every move the same prior, so PUCT
+// explores all of them alike, as UCB1 does.
+func UniformPrior(bd *Board, move int, player int) float64 {
+	return 1
+}
+
+// GreedyPrior favors moves that put more stones
+// in player's store right away, one more than GreedyPlayer's
+// gain, without the bonus move, so every move has some chance.
+func GreedyPrior(bd *Board, move int, player int) float64 {
+	view := *bd
+	if player == MINIMIZER {
+		view = bd.Mirror()
+	}
+	return float64(1 + greedyGain(view, move, false))
+}
+
+// DefaultRAVEK is EnableRAVE's equivalence parameter unless it gets
+// told otherwise: RAVE and a child's own wins count the same at
+// about DefaultRAVEK/3 visits.
+const DefaultRAVEK = 1000
+
+// NewMCTS sets up Monte Carlo Tree Search with UCB1,
+// doing iterations playouts per move. Its random numbers start
+// from the time of day, unless Seed says otherwise.
+func NewMCTS(iterations int, uctk float64) *MCTS {
+	return &MCTS{
+		iterations: iterations,
+		uctk:       uctk,
+		Rollout:    RandomRollout{},
+		rng:        rand.New(rand.NewSource(time.Now().UTC().UnixNano())),
+	}
+}
+
+// Seed starts p's random numbers over from seed, so that its
+// searches make the same choices every time.
+func (p *MCTS) Seed(seed int64) {
+	p.rng = rand.New(rand.NewSource(seed))
+}
+
+// EnableRAVE turns RAVE on or off. A k of 0 or less
+// keeps the current equivalence parameter, DefaultRAVEK to start.
+func (p *MCTS) EnableRAVE(enabled bool, k float64) {
+	p.raveEnabled = enabled
+	if p.raveK == 0 {
+		p.raveK = DefaultRAVEK
+	}
+	if k > 0 {
+		p.raveK = k
+	}
+}
+
+// ChooseMove is a ChooserFunction
+func (p *MCTS) ChooseMove(ctx context.Context, bd Board, print bool) (bestpit int, value int, err error) {
+	return p.chooseMonteCarlo(ctx, bd, print)
+}
+
+type gameState struct {
+	board Board
+}
+
+type Node struct {
+	move         int
+	player       int // who made move
+	next         int // who moves next, player again after a bonus move
+	childNodes   []*Node
+	untriedMoves []int
+	parent       *Node
+	visits       int
+	wins         float64
+	rave         *raveStats // nil unless RAVE is on
+	prior        float64    // PUCT's prior probability of move, 0 unless PUCT is on
+}
+
+// raveStats are "all moves as first" counts for one node: visits and
+// wins, for the player to move at the node, of playouts through the
+// node where that player played a pit at any point below it.
+type raveStats struct {
+	visits [MaxPits]int
+	wins   [MaxPits]float64
+}
+
+// raveMove is one move of a playout, for RAVE to credit.
+type raveMove struct {
+	player, pit int
+}
+
+// chooseMonteCarlo - based on current board, return the best pit
+// for MAXIMIZER to pick up and drop down the board. If ctx gets
+// cancelled, the best pit is the one with the most visits so far.
+// If MAXIMIZER's pits are all empty, it's -1, with ErrNoMoves.
+func (p *MCTS) chooseMonteCarlo(ctx context.Context, bd Board, print bool) (bestpit int, value int, err error) {
+	p.NodesEvaluated = 0
+	if pit, found := p.Book.lookup(bd); found {
+		if p.Verbose {
+			fmt.Printf("Opening book move %d\n", pit)
+		}
+		p.moveNode = nil
+		return pit, 0, nil
+	}
+
+	p.arena = NewNodeArena(p.iterations + 1)
+	var root *Node
+	if root = p.reusableSubtree(bd); root != nil {
+		if p.Verbose {
+			fmt.Printf("Reusing %d visits from the previous search\n", root.visits)
+		}
+		root.parent = nil
+		root.player = MINIMIZER // so MAXIMIZER moves next, even after a bonus move
+		root.next = MAXIMIZER
+		root, err = p.grow(ctx, root, bd, p.iterations)
+	} else {
+		root, err = p.search(ctx, bd, bd.LegalMoves(MAXIMIZER), p.iterations)
+	}
+	if err != nil && err != ErrSearchCancelled {
+		return -1, 0, err
+	}
+	if len(root.childNodes) == 0 {
+		// MAXIMIZER had no moves to try, so nothing got expanded
+		p.moveNode = nil
+		return -1, 0, ErrNoMoves
+	}
+
+	p.moveNode = root
+	p.moveBoard = bd
+	if p.Verbose {
+		fmt.Printf("Tree balance %.3f\n", p.treeBalance())
+		fmt.Printf("MCTS best line: %s\n", formatLine(p.BestLine()))
+	}
+
+	bestChild := root.mostVisited()
+	return bestChild.move, int(bestChild.wins / float64(bestChild.visits) * 100.), err
+}
+
+// search does iterations of MCTS, starting with MAXIMIZER to move in bd,
+// and returns the root of the tree it built. Only moves get tried at
+// the root, so a search can be restricted to a few candidate moves.
+func (p *MCTS) search(ctx context.Context, bd Board, moves []int, iterations int) (*Node, error) {
+	root := &Node{
+		player:       MINIMIZER, // opponent made last move
+		next:         MAXIMIZER,
+		untriedMoves: moves,
+	}
+	// by definition the next player is MAXIMIZER.
+	return p.grow(ctx, root, bd, iterations)
+}
+
+// reuseDepth is as many moves past the previous search's root as
+// reusableSubtree looks. A computer move and a reply are only 2,
+// but every bonus move adds another.
+const reuseDepth = 12
+
+// reusableSubtree finds the node in the previous search's tree that
+// has bd's board, MAXIMIZER to move: the computer's last move and the
+// opponent's reply, bonus moves and all, got played from the old root.
+// The visits below that node are still good. It gives nil if there's
+// no such node, for a new game, or after an opening book move.
+func (p *MCTS) reusableSubtree(bd Board) *Node {
+	if p.moveNode == nil {
+		return nil
+	}
+	return findBoard(p.moveNode, p.moveBoard, MAXIMIZER, &bd, reuseDepth)
+}
+
+// findBoard searches the tree at n, which has board state and next to
+// move, for a node with target's stones, MAXIMIZER to move, at most
+// depth moves down. Stones never leave a store, so there's no point in
+// looking below a board with more stones in a store than target has.
+func findBoard(n *Node, state Board, next int, target *Board, depth int) *Node {
+	if next == MAXIMIZER && state.maxpits == target.maxpits && state.minpits == target.minpits {
+		return n
+	}
+	if depth == 0 || state.Store(MAXIMIZER) > target.Store(MAXIMIZER) || state.Store(MINIMIZER) > target.Store(MINIMIZER) {
+		return nil
+	}
+	for _, c := range n.childNodes {
+		child := state
+		childNext, _, err := MakeMove(&child, c.move, next)
+		if err != nil {
+			panic(err) // tree only has moves that were legal when expanded
+		}
+		if found := findBoard(c, child, childNext, target, depth-1); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// grow does iterations of MCTS on the tree at root, which has board bd,
+// MAXIMIZER to move. root can be a brand new node, or one
+// with visits and children from an earlier search. If ctx gets
+// cancelled, it stops early, and gives root with ErrSearchCancelled.
+func (p *MCTS) grow(ctx context.Context, root *Node, bd Board, iterations int) (*Node, error) {
+	state := &Board{pits: bd.pits, rules: bd.rules}
+	start := time.Now()
+	var deadline, budgetDeadline time.Time
+	if p.Clock != nil {
+		deadline = start.Add(p.Clock.Budget())
+	}
+	if p.TimeBudget > 0 {
+		budgetDeadline = start.Add(p.TimeBudget)
+	}
+
+	iter := 0
+	for ; iter < iterations || p.TimeBudget > 0; iter++ {
+		if iter%cancelCheckIterations == 0 && iter > 0 && ctx.Err() != nil {
+			if p.Verbose {
+				fmt.Printf("Search cancelled after %d iterations\n", iter)
+			}
+			return root, ErrSearchCancelled
+		}
+		if p.Clock != nil && iter%clockCheckIterations == 0 && iter > 0 && time.Now().After(deadline) {
+			if p.Verbose {
+				fmt.Printf("Out of time for the move after %d iterations\n", iter)
+			}
+			break
+		}
+		if p.TimeBudget > 0 && iter&(budgetCheckIterations-1) == 0 && iter > 0 && time.Now().After(budgetDeadline) {
+			break
+		}
+		if p.Verbose {
+			fmt.Printf("\n\nIteration %d\n", iter)
+		}
+		// reset game state tracker
+		for i := 0; i <= bd.pits; i++ {
+			state.maxpits[i] = bd.maxpits[i]
+			state.minpits[i] = bd.minpits[i]
+		}
+		state.player = root.player
+		p.playout = p.playout[:0]
+		p.NodesEvaluated++
+
+		node, nextPlayer := p.selectNode(root, state)
+		// IsTerminal, not CheckEnd: simulate does the one and only
+		// end-of-game sweep, and works out the winner from that.
+		if !state.IsTerminal() && len(node.untriedMoves) > 0 {
+			var err error
+			node, nextPlayer, err = p.expand(node, state, nextPlayer)
+			if err != nil {
+				return nil, err
+			}
+		}
+		winner := p.simulate(state, nextPlayer)
+		p.backpropagate(node, winner)
+		if p.raveEnabled {
+			p.backpropagateRAVE(node, winner)
+		}
+	}
+	if p.Verbose && p.TimeBudget > 0 {
+		fmt.Printf("%d iterations in %v\n", iter, time.Since(start))
+	}
+
+	return root, nil
+}
+
+// mostVisited gives the child with the largest number of visits,
+// the move MCTS thinks best. n has to have children.
+func (n *Node) mostVisited() *Node {
+	bestChild := n.childNodes[0]
+	mostVisits := bestChild.visits
+
+	for _, c := range n.childNodes[1:] {
+		if c.visits > mostVisits {
+			bestChild = c
+			mostVisits = bestChild.visits
+		}
+	}
+	return bestChild
+}
+
+// selectNode is the Selection step. Starting at root, with state
+// set up as root's board, it follows the best child by UCB1 until it
+// gets to a node with untried moves, or a leaf. It makes each move
+// on the way down on state, and returns the node it got to and
+// the player who moves next.
+func (p *MCTS) selectNode(root *Node, state *Board) (*Node, int) {
+	node := root
+	nextPlayer := -root.player
+
+	if p.Verbose {
+		fmt.Printf("0 game, %d, next %d:\n%v\n", state.player, nextPlayer, state)
+	}
+
+	for len(node.untriedMoves) == 0 && len(node.childNodes) > 0 {
+		oldmove, oldplayer := node.move, node.player
+		node = node.selectBestChild(p.childScore)
+		if p.Verbose {
+			fmt.Printf("Best child of %d by %d:%d by %d\n", oldmove, oldplayer, node.move, node.player)
+		}
+		// Filling state from a game tree, so use node.move, node.player,
+		// ignoring nextPlayer for now.
+		var err error
+		nextPlayer, _, err = MakeMove(state, node.move, node.player)
+		if err != nil {
+			panic(err) // tree only has moves that were legal when expanded
+		}
+		if p.Verbose {
+			fmt.Printf("after %d/%d, %d, next %d:\n%s\n", node.move, node.player, state.player, nextPlayer, state)
+		}
+	}
+
+	if p.Verbose {
+		fmt.Printf("1 game, %d, next %d:\n%v\n", state.player, nextPlayer, state)
+	}
+	return node, nextPlayer
+}
+
+// expand is the Expansion step. It makes one of node's untried
+// moves, chosen at random, for nextPlayer on state, and adds a child
+// node for it. node has to have untried moves, and the game in state
+// can't be over. It returns the new child, and who moves after it.
+func (p *MCTS) expand(node *Node, state *Board, nextPlayer int) (*Node, int, error) {
+	if p.Verbose {
+		fmt.Printf("Expansion, player %d, next %d, untried moves %v\n", node.player, nextPlayer, node.untriedMoves)
+	}
+	if nextPlayer != node.next {
+		return nil, 0, fmt.Errorf("expansion, player %d to move, but node %d/%d has %d to move, moves from root %s\n%s",
+			nextPlayer, node.move, node.player, node.next, node.trace(), state)
+	}
+	mv := node.randomUntried(p.rng)
+	if p.Verbose {
+		fmt.Printf("Expansion, player %d, chose move %d, untried moves %v\n", node.player, mv, node.untriedMoves)
+	}
+	var prior float64
+	if p.Formula == "puct" {
+		prior = p.prior(node, state, mv, nextPlayer)
+	}
+
+	nextPlayer, _, err := MakeMove(state, mv, nextPlayer)
+	if err != nil {
+		return nil, 0, err
+	}
+	child, err := node.addChild(p.arena, mv, nextPlayer, state)
+	if err != nil {
+		return nil, 0, err
+	}
+	child.prior = prior
+	if p.Verbose {
+		fmt.Printf("new child of %d/%d: %d/%d, untried %v\n",
+			node.move, node.player,
+			child.move, child.player,
+			child.untriedMoves,
+		)
+		fmt.Printf("2 game, %d:\n%v\n", state.player, state)
+	}
+	return child, nextPlayer, nil
+}
+
+// simulate is the Simulation step, a lightweight playout. Starting
+// with nextPlayer to move, it makes the Rollout policy's moves on state until
+// the game ends, and returns the winner, UNSET for a tie. A game that's
+// already over in state just gets its winner returned. CheckEnd sweeps
+// state at the end, so nothing should look at state after simulate
+// but before the next iteration resets it.
+func (p *MCTS) simulate(state *Board, nextPlayer int) int {
+	gameEnd, winner := CheckEnd(state)
+	if gameEnd {
+		if p.Verbose {
+			fmt.Printf("Game end %v, winner %d\n", gameEnd, winner)
+		}
+		return winner
+	}
+	if p.Verbose {
+		fmt.Printf("Simulation begins, %d:\n%v\n", nextPlayer, state)
+	}
+	rollout := p.Rollout
+	if rollout == nil {
+		rollout = RandomRollout{}
+	}
+	for !gameEnd {
+		mv := rollout.SelectMove(state, nextPlayer, p.rng)
+		if p.raveEnabled {
+			p.playout = append(p.playout, raveMove{player: nextPlayer, pit: mv})
+		}
+		var err error
+		nextPlayer, _, err = MakeMove(state, mv, nextPlayer)
+		if err != nil {
+			panic(err) // rollouts only pick non-empty pits
+		}
+		gameEnd, winner = CheckEnd(state)
+	}
+	if p.Verbose {
+		fmt.Printf("Simulation ends, %d, winner %d:\n%v\n", nextPlayer, winner, state)
+	}
+	return winner
+}
+
+// backpropagate is the Back propagation step. It credits node and
+// all its ancestors with a visit, and a win for the nodes whose
+// player is winner, half a win each for a tie.
+func (p *MCTS) backpropagate(node *Node, winner int) {
+	for node != nil {
+		node.visits++
+		if winner == node.player {
+			node.wins++
+		} else if winner == 0 {
+			node.wins += 0.5
+		}
+		node = node.parent
+	}
+}
+
+// backpropagateRAVE updates the RAVE counts of node and its
+// ancestors. Every node gets credit for each pit its player to move
+// played anywhere below it, in the tree or in the playout, counting
+// only the first time the pit got played.
+func (p *MCTS) backpropagateRAVE(node *Node, winner int) {
+	// moves from the root down to node, then the playout's
+	line := p.raveLine[:0]
+	for n := node; n.parent != nil; n = n.parent {
+		line = append(line, raveMove{player: n.player, pit: n.move})
+	}
+	depth := len(line)
+	for i, j := 0, depth-1; i < j; i, j = i+1, j-1 {
+		line[i], line[j] = line[j], line[i]
+	}
+	line = append(line, p.playout...)
+	p.raveLine = line
+
+	for n := node; n != nil; n, depth = n.parent, depth-1 {
+		if depth >= len(line) {
+			continue // game over at n, nothing below it
+		}
+		if n.rave == nil {
+			n.rave = &raveStats{}
+		}
+		mover := line[depth].player
+		var seen [MaxPits]bool
+		for _, m := range line[depth:] {
+			if m.player != mover || seen[m.pit] {
+				continue
+			}
+			seen[m.pit] = true
+			n.rave.visits[m.pit]++
+			if winner == mover {
+				n.rave.wins[m.pit]++
+			} else if winner == UNSET {
+				n.rave.wins[m.pit] += 0.5
+			}
+		}
+	}
+}
+
+// BestLine gives the pits of the line of play the most recent search
+// thinks likeliest, following the most-visited child from the root down.
+// Moves alternate players, except after a bonus move.
+func (p *MCTS) BestLine() []int {
+	if p.moveNode == nil {
+		return nil
+	}
+	var line []int
+	for n := p.moveNode; len(n.childNodes) > 0; {
+		n = n.mostVisited()
+		line = append(line, n.move)
+	}
+	return line
+}
+
+// formatLine writes out a line of play like "3→1→2".
+func formatLine(line []int) string {
+	var sb strings.Builder
+	for i, pit := range line {
+		if i > 0 {
+			sb.WriteString("→")
+		}
+		sb.WriteString(strconv.Itoa(pit))
+	}
+	return sb.String()
+}
+
+// treeBalance gives the ratio of the deepest leaf's depth to the
+// average leaf depth of the most recent search's tree. Near 1.0 means
+// all lines got about the same exploration, large values mean the
+// search concentrated on a few deep lines, maybe UCTK needs tuning.
+func (p *MCTS) treeBalance() float64 {
+	if p.moveNode == nil {
+		return 0
+	}
+	var leaves, depthSum, maxDepth int
+	var walk func(n *Node, depth int)
+	walk = func(n *Node, depth int) {
+		if len(n.childNodes) == 0 {
+			leaves++
+			depthSum += depth
+			if depth > maxDepth {
+				maxDepth = depth
+			}
+			return
+		}
+		for _, c := range n.childNodes {
+			walk(c, depth+1)
+		}
+	}
+	walk(p.moveNode, 0)
+	if depthSum == 0 {
+		return 0
+	}
+	return float64(maxDepth) / (float64(depthSum) / float64(leaves))
+}
+
+// randomMove picks one of player's non-empty pits, with rng. There has
+// to be one, CheckEnd() ends the game when either side runs out of stones.
+func (bd *Board) randomMove(player int, rng *rand.Rand) int {
+	var buf [MaxPits]int
+	moves := bd.appendLegalMoves(buf[:0], player)
+	return moves[rng.Intn(len(moves))]
+}
+
+func (n *Node) randomUntried(rng *rand.Rand) int {
+	ln := len(n.untriedMoves)
+	randIdx := rng.Intn(ln)
+	ln--
+	mv := n.untriedMoves[randIdx]
+	n.untriedMoves[randIdx] = n.untriedMoves[ln]
+	n.untriedMoves = n.untriedMoves[:ln]
+	return mv
+}
+
+// addChild adds a node for mv, taken from arena, to n's children.
+func (n *Node) addChild(arena *NodeArena, mv int, nextPlayer int, state *Board) (*Node, error) {
+	if mv < 0 || mv >= state.pits {
+		return nil, fmt.Errorf("addChild, move %d illegal, parent node: %d/%d, untried moves %v, next player %d, state.player %d\n%s",
+			mv, n.move, n.player, n.untriedMoves, nextPlayer, state.player, state)
+	}
+	newChild := arena.newNode()
+	*newChild = Node{
+		move:         mv,
+		player:       state.player,
+		next:         nextPlayer,
+		parent:       n,
+		untriedMoves: state.LegalMoves(nextPlayer),
+	}
+	n.childNodes = append(n.childNodes, newChild)
+	return newChild, nil
+}
+
+// trace writes out the moves from the root down to n,
+// like "1:3 1:5 -1:2", player and pit.
+func (n *Node) trace() string {
+	var moves []string
+	for ; n.parent != nil; n = n.parent {
+		moves = append([]string{fmt.Sprintf("%d:%d", n.player, n.move)}, moves...)
+	}
+	return strings.Join(moves, " ")
+}
+
+// selectBestChild gives the child with the highest score.
+func (n *Node) selectBestChild(score func(*Node) float64) *Node {
+	bestScore := score(n.childNodes[0])
+	bestChild := n.childNodes[0]
+	for _, c := range n.childNodes[1:] {
+		if s := score(c); s > bestScore {
+			bestScore = s
+			bestChild = c
+		}
+	}
+	return bestChild
+}
+
+// childScore is how selectNode rates a child: UCB1, UCB1 with RAVE,
+// or PUCT, with uctk as PUCT's exploration constant. RAVE only
+// works with UCB1.
+func (p *MCTS) childScore(c *Node) float64 {
+	switch {
+	case p.raveEnabled:
+		return c.uctRave(p.uctk, p.raveK)
+	case p.Formula == "puct":
+		return c.puct(p.uctk)
+	}
+	return c.ucb1(p.uctk)
+}
+
+// prior is PriorFn's weight for player playing mv at node, whose board
+// is state, as a fraction of the weights of all node's legal moves.
+// expand has already taken mv out of node's untried moves.
+func (p *MCTS) prior(node *Node, state *Board, mv int, player int) float64 {
+	fn := p.PriorFn
+	if fn == nil {
+		fn = UniformPrior
+	}
+	w := fn(state, mv, player)
+	total := w
+	for _, m := range node.untriedMoves {
+		total += fn(state, m, player)
+	}
+	for _, c := range node.childNodes {
+		total += fn(state, c.move, player)
+	}
+	return w / total
+}
+
+// puct is Q(s,a) + c * P(s,a) * sqrt(N(s)) / (1 + N(s,a)): win rate,
+// plus exploration that shrinks as n gets visits, in proportion to
+// n's prior.
+func (n *Node) puct(c float64) float64 {
+	v := float64(n.visits)
+	return n.wins/v + c*n.prior*math.Sqrt(float64(n.parent.visits))/(1+v)
+}
+
+// uctRave is ucb1, but with the win rate a blend of n's own and its
+// parent's RAVE win rate for n's move. RAVE counts for a lot while n
+// has few visits, less and less as n gets more of its own.
+func (n *Node) uctRave(uctk, raveK float64) float64 {
+	r := n.parent.rave
+	if r == nil || r.visits[n.move] == 0 {
+		return n.ucb1(uctk)
+	}
+	v := float64(n.visits)
+	beta := math.Sqrt(raveK / (3*v + raveK))
+	q := (1-beta)*n.wins/v + beta*r.wins[n.move]/float64(r.visits[n.move])
+	return q + uctk*math.Sqrt(math.Log(float64(n.parent.visits+1))/v)
+}
+
+func (n *Node) ucb1(uctk float64) float64 {
+	v := float64(n.visits)
+	return n.wins/v +
+		uctk*math.Sqrt(math.Log(float64(n.parent.visits+1))/v)
+}
@@ -0,0 +1,161 @@
+package kalah
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// TestChooseMonteCarloNoMoves has MCTS choose a move for a late game
+// board where MAXIMIZER has no stones left in any pit, which used
+// to panic on the root's empty list of children.
+func TestChooseMonteCarloNoMoves(t *testing.T) {
+	bd, err := BoardFromFEN("0.0.0.0.0.0/0.3.0.1.0.2 20 22 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, formula := range []string{"", "puct"} {
+		for _, rave := range []bool{false, true} {
+			p := NewMCTS(100, 1.414)
+			p.Seed(808)
+			p.Formula = formula
+			p.EnableRAVE(rave, 0)
+			pit, value, err := p.ChooseMove(context.Background(), bd, false)
+			if pit != -1 || value != 0 || err != ErrNoMoves {
+				t.Errorf("formula %q, RAVE %v: got %d, %d, %v, want -1, 0, %v", formula, rave, pit, value, err, ErrNoMoves)
+			}
+		}
+	}
+}
+
+// TestChooseMonteCarloLastMove checks that a late game board with
+// only one stone left for MAXIMIZER gets that pit.
+func TestChooseMonteCarloLastMove(t *testing.T) {
+	bd, err := BoardFromFEN("0.0.0.1.0.0/0.3.0.1.0.2 19 22 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewMCTS(100, 1.414)
+	p.Seed(808)
+	pit, _, err := p.ChooseMove(context.Background(), bd, false)
+	if pit != 3 || err != nil {
+		t.Errorf("got pit %d, %v, want 3, nil", pit, err)
+	}
+}
+
+// TestAddChild checks that addChild won't add a child for a pit that
+// isn't on the board, and that it takes no node from the arena when it
+// doesn't, then adds one for a pit that is.
+func TestAddChild(t *testing.T) {
+	bd := NewBoard(4)
+	root := &Node{move: -1, player: MINIMIZER, next: MAXIMIZER, untriedMoves: bd.LegalMoves(MAXIMIZER)}
+	arena := NewNodeArena(8)
+	for _, mv := range []int{-1, 6, MaxPits} {
+		child, err := root.addChild(arena, mv, MINIMIZER, &bd)
+		if child != nil || err == nil {
+			t.Errorf("move %d: got %v, %v, want an error", mv, child, err)
+		}
+	}
+	if len(root.childNodes) != 0 || arena.next != 0 {
+		t.Fatalf("%d children, %d arena nodes used after errors, want none", len(root.childNodes), arena.next)
+	}
+
+	next, _, err := MakeMove(&bd, 2, MAXIMIZER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := root.addChild(arena, 2, next, &bd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root.childNodes) != 1 || root.childNodes[0] != child || child.parent != root || arena.next != 1 {
+		t.Errorf("child %p not added to root's children %v from the arena", child, root.childNodes)
+	}
+	if child.move != 2 || child.player != MAXIMIZER || child.next != next || !equalInts(child.untriedMoves, bd.LegalMoves(next)) {
+		t.Errorf("child move %d, player %d, next %d, untried %v, want 2, %d, %d, %v",
+			child.move, child.player, child.next, child.untriedMoves, MAXIMIZER, next, bd.LegalMoves(next))
+	}
+}
+
+// TestSimulate checks simulate's playouts: on a game that's already
+// over, it gives PeekEnd's winner without a move, and from random
+// positions, with each rollout policy, it plays to the end, and its
+// winner is the one the board says, with the moves it recorded for
+// RAVE replaying to the same board.
+func TestSimulate(t *testing.T) {
+	over := []struct {
+		fen  string
+		want int
+	}{
+		{"0.0.0.0.0.0/0.3.0.1.0.2 20 22 1", MINIMIZER}, // MAXIMIZER out of stones
+		{"0.0.0.0.0.0/0.3.0.1.0.2 24 18 1", UNSET},     // a tie, once MINIMIZER's are swept
+		{"0.2.0.0.0.0/0.0.0.0.0.0 30 16 1", MAXIMIZER}, // MINIMIZER out of stones
+		{"4.0.0.0.0.0/0.0.0.0.0.0 19 25 1", MINIMIZER}, // by two stones, after the sweep
+	}
+	for _, tt := range over {
+		bd, err := BoardFromFEN(tt.fen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if end, winner := PeekEnd(bd); !end || winner != tt.want {
+			t.Fatalf("%s: PeekEnd says %v, %d, want the end, %d", tt.fen, end, winner, tt.want)
+		}
+		for _, next := range []int{MAXIMIZER, MINIMIZER} {
+			p := NewMCTS(1, 1.414)
+			p.EnableRAVE(true, 0)
+			state := bd.Clone()
+			if got := p.simulate(&state, next); got != tt.want {
+				t.Errorf("%s, %d to move: winner %d, want %d", tt.fen, next, got, tt.want)
+			}
+			if len(p.playout) != 0 {
+				t.Errorf("%s, %d to move: playout %v, want no moves", tt.fen, next, p.playout)
+			}
+		}
+	}
+
+	rollouts := []RolloutPolicy{RandomRollout{}, GreedyRollout{}, MixedRollout{P: 0.5}}
+	rng := rand.New(rand.NewSource(771))
+	for i := 0; i < 300; i++ {
+		bd, ok := randomPosition(rng, 1+rng.Intn(MaxPits), 1+rng.Intn(6), rng.Intn(20))
+		if !ok {
+			continue
+		}
+		p := NewMCTS(1, 1.414)
+		p.Seed(int64(i))
+		p.Rollout = rollouts[i%len(rollouts)]
+		p.EnableRAVE(true, 0)
+		next := MAXIMIZER
+		if rng.Intn(2) == 0 {
+			next = MINIMIZER
+		}
+		state := bd.Clone()
+		winner := p.simulate(&state, next)
+		if !state.IsTerminal() || state.TotalStones() != bd.TotalStones() {
+			t.Fatalf("%s: simulate stopped at %s, %d stones, want the end with %d", bd.FEN(), state.FEN(), state.TotalStones(), bd.TotalStones())
+		}
+		want := UNSET
+		if d := state.Store(MAXIMIZER) - state.Store(MINIMIZER); d > 0 {
+			want = MAXIMIZER
+		} else if d < 0 {
+			want = MINIMIZER
+		}
+		if winner != want {
+			t.Errorf("%s: winner %d, but the game ended %s", bd.FEN(), winner, state.FEN())
+		}
+		replay := bd.Clone()
+		player := next
+		for _, mv := range p.playout {
+			if mv.player != player {
+				t.Fatalf("%s: playout move %v, %d to move", bd.FEN(), mv, player)
+			}
+			var err error
+			if player, _, err = MakeMove(&replay, mv.pit, mv.player); err != nil {
+				t.Fatalf("%s: playout move %v: %v", bd.FEN(), mv, err)
+			}
+		}
+		CheckEnd(&replay)
+		if !replay.Equal(state) {
+			t.Errorf("%s: playout %v replays to %s, simulate ended at %s", bd.FEN(), p.playout, replay.FEN(), state.FEN())
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package kalah
+
+import (
+	"context"
+	"fmt"
+)
+
+// chooseMTDF finds MAXIMIZER's best move on bd by MTD(f): a series of
+// zero-window searches, each of which only says whether the value is
+// at least some beta, closing in on the value from firstGuess. Each
+// search gives a bound, an upper bound if it fails low, a lower bound
+// if it fails high, and the next search goes at the new bound. It's
+// done when the bounds meet. The closer firstGuess is, the fewer
+// searches it takes. Without a transposition table, every search
+// starts over from scratch, but whatever it takes, the value comes
+// out the same as alpha/beta's. If ctx gets cancelled, it stops
+// between searches, giving the last move that failed high, or if none
+// has, the best move of the last search, with ErrSearchCancelled.
+// If MAXIMIZER has no moves, it's -1, with ErrNoMoves.
+func (ab *AlphaBeta) chooseMTDF(ctx context.Context, bd Board, firstGuess int, print bool) (bestpit int, bestvalue int, err error) {
+	if bd.LegalMoves(MAXIMIZER) == nil {
+		return -1, 0, ErrNoMoves
+	}
+	g := firstGuess
+	lower, upper := 2*LOSS, 2*WIN
+	searches := 0
+	failedHigh := false
+	for lower < upper {
+		if searches > 0 && ctx.Err() != nil {
+			return bestpit, g, ErrSearchCancelled
+		}
+		beta := g
+		if g == lower {
+			beta = g + 1
+		}
+		var pit int
+		g, pit = ab.mtdfRoot(bd, beta)
+		searches++
+		if g < beta {
+			upper = g
+			if !failedHigh {
+				bestpit = pit
+			}
+		} else {
+			lower = g
+			bestpit = pit
+			failedHigh = true
+		}
+	}
+	if ab.Verbose {
+		fmt.Printf("MTD(f): %d zero-window searches from first guess %d\n", searches, firstGuess)
+	}
+	return bestpit, g, nil
+}
+
+// mtdfRoot tries MAXIMIZER's moves in bd with a zero window at beta,
+// stopping at the first that gets beta or better. Like searchRoot,
+// a move that earns a bonus move gets searched as MINIMIZER's turn.
+func (ab *AlphaBeta) mtdfRoot(bd Board, beta int) (value int, bestpit int) {
+	value = 2 * LOSS // -infinity
+	var bd2 Board
+	var buf [MaxPits]int
+	for _, pit := range bd.appendLegalMoves(buf[:0], MAXIMIZER) {
+		bd2 = bd.Clone()
+		if _, _, err := MakeMove(&bd2, pit, MAXIMIZER); err != nil {
+			panic(err) // only legal moves, can't happen
+		}
+		var v int
+		if end, winner := CheckEnd(&bd2); end {
+			v = ab.terminalValue(winner, 0)
+		} else {
+			v = ab.alphabetaZW(&bd2, 1, MINIMIZER, beta)
+		}
+		if v > value {
+			value, bestpit = v, pit
+		}
+		if value >= beta {
+			break
+		}
+	}
+	return value, bestpit
+}
+
+// alphabetaZW is alpha/beta with the zero window beta-1, beta. It's
+// fail-soft: the value it gives is a bound past beta-1 or beta, as
+// far past as the search could tell, not just beta-1 or beta, so
+// MTD(f) can take bigger steps towards the real value. With a TT,
+// the zero-window searches share what they find, the way MTD(f)
+// is meant to work.
+func (ab *AlphaBeta) alphabetaZW(bd *Board, ply, player, beta int) int {
+	ab.enterNode(beta-1, beta)
+	if ply > ab.maxPly {
+		return ab.evaluate(bd, ply)
+	}
+	var key uint64
+	useTT := ab.TT != nil && ab.maxPly-ply >= ttMinDepth
+	if useTT {
+		key = bd.hashToMove(player)
+		if value, found := ab.TT.probe(key, ab.maxPly-ply, ply, beta-1, beta); found {
+			return value
+		}
+	}
+
+	var moves [MaxPits]int
+	var bd2 Board
+	own := &bd.maxpits
+	best := 2 * LOSS // -infinity
+	if player == MINIMIZER {
+		own = &bd.minpits
+		best = 2 * WIN
+	}
+	n := ab.orderMoves(own, bd.pits, player, ply, &moves)
+	for _, pit := range moves[:n] {
+		bd2 = bd.Clone()
+		nextplayer, plydelta, err := MakeMove(&bd2, pit, player)
+		if err != nil {
+			panic(err) // orderMoves only gives non-empty pits
+		}
+		var value int
+		if end, winner := CheckEnd(&bd2); end {
+			value = ab.terminalValue(winner, ply)
+		} else {
+			value = ab.alphabetaZW(&bd2, ply+plydelta, nextplayer, beta)
+		}
+		if (player == MAXIMIZER && value > best) || (player == MINIMIZER && value < best) {
+			best = value
+		}
+		if (player == MAXIMIZER && best >= beta) || (player == MINIMIZER && best < beta) {
+			ab.recordCutoff(player, pit, ply)
+			if !bd.captures(player, pit) {
+				ab.recordKiller(pit, ply)
+			}
+			break
+		}
+	}
+	if useTT {
+		ab.TT.store(key, ab.maxPly-ply, ply, best, beta-1, beta)
+	}
+	return best
+}
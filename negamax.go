@@ -0,0 +1,77 @@
+package kalah
+
+// negamax is alphaBeta from the point of view of player, the player to
+// move: values are good for player if they're big, whoever player is,
+// so one loop does for both players. A move that hands the turn over
+// gets the opponent's value for what follows, negated, searched with
+// the window negated and swapped. A bonus move is still player's turn,
+// so it gets searched with the same window, and nothing negated. It
+// finds the same values alphaBeta does, pruning and all.
+func (ab *AlphaBeta) negamax(bd *Board, ply, player, alpha, beta int) (value int) {
+	ab.enterNode(alpha, beta)
+	h := ab.line.enter()
+	defer ab.line.leave()
+	if ply > ab.maxPly {
+		return player * ab.evaluate(bd, ply)
+	}
+	if player == MAXIMIZER && ab.nullMoveOK(bd, ply, beta) && -ab.negamax(bd, ply+nullMoveR+1, MINIMIZER, -beta, -beta+1) >= beta {
+		return beta
+	}
+
+	own := &bd.maxpits
+	if player == MINIMIZER {
+		own = &bd.minpits
+	}
+	futile := ab.futile(bd, ply) && player*ab.evaluate(bd, ply)+futilityMargin <= alpha
+
+	var moves [MaxPits]int
+	var bd2 Board
+	n := ab.orderMoves(own, bd.pits, player, ply, &moves)
+	for _, pit := range moves[:n] {
+		if futile && ab.quiet(bd, player, pit) {
+			continue
+		}
+		bd2 = bd.Clone()
+		ab.line.child(h)
+		node := ab.exportTree.enter(pit, player)
+		if player == MAXIMIZER {
+			ab.trace.enter(player, pit, ply, alpha, beta)
+		} else {
+			ab.trace.enter(player, pit, ply, -beta, -alpha)
+		}
+		nextplayer, plydelta, err := MakeMove(&bd2, pit, player)
+		if err != nil {
+			panic(err) // orderMoves only gives non-empty pits
+		}
+		if end, winner := CheckEnd(&bd2); end {
+			value = player * ab.terminalValue(winner, ply)
+		} else if nextplayer == player {
+			value = ab.negamax(&bd2, ply+plydelta, player, alpha, beta)
+		} else {
+			value = -ab.negamax(&bd2, ply+plydelta, nextplayer, -beta, -alpha)
+		}
+		if value > alpha {
+			alpha = value
+			ab.line.update(h, pit)
+		}
+		ab.exportTree.leave(node, player*value, beta <= alpha)
+		ab.trace.leave(player * value)
+		if beta <= alpha {
+			ab.recordCutoff(player, pit, ply)
+			if !bd.captures(player, pit) {
+				ab.recordKiller(pit, ply)
+			}
+			return value
+		}
+	}
+	return alpha
+}
+
+// negamaxAs is negamax with alpha, beta and the value from MAXIMIZER's
+// point of view, the way alphaBeta takes and gives them, whoever moves.
+func (ab *AlphaBeta) negamaxAs(bd *Board, ply, player, alpha, beta int) int {
+	if player == MAXIMIZER {
+		return ab.negamax(bd, ply, player, alpha, beta)
+	}
+	return -ab.negamax(bd, ply, player, -beta, -alpha)
+}
@@ -0,0 +1,63 @@
+package kalah
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+// randomPosition plays up to plies random moves from a new game of
+// pits pits and stones stones per pit, and keeps going until it's
+// MAXIMIZER's move, the way searches see a board. It gives false if
+// the game ended first.
+func randomPosition(rng *rand.Rand, pits, stones, plies int) (Board, bool) {
+	bd := NewBoardPits(pits, stones)
+	player := MAXIMIZER
+	for ply := 0; ply < plies || player != MAXIMIZER; ply++ {
+		moves := bd.LegalMoves(player)
+		next, _, err := MakeMove(&bd, moves[rng.Intn(len(moves))], player)
+		if err != nil {
+			panic(err)
+		}
+		if end, _ := CheckEnd(&bd); end {
+			return bd, false
+		}
+		player = next
+	}
+	bd.SetPlayer(MINIMIZER)
+	return bd, true
+}
+
+// TestNegamaxSameAsAlphaBeta searches random positions at several
+// depths with plain alpha/beta and with negamax, with and without the
+// pruning that both do, and checks that they choose the same move,
+// with the same value.
+func TestNegamaxSameAsAlphaBeta(t *testing.T) {
+	rng := rand.New(rand.NewSource(782))
+	for i := 0; i < 200; i++ {
+		bd, ok := randomPosition(rng, 6, 4, rng.Intn(30))
+		if !ok {
+			continue
+		}
+		depth := 1 + rng.Intn(4)
+		futility, nullMove := rng.Intn(2) == 0, rng.Intn(2) == 0
+
+		ab := NewAlphaBeta(depth)
+		ab.Futility, ab.NullMove = futility, nullMove
+		abPit, abValue, err := ab.ChooseMove(context.Background(), bd, false)
+		if err != nil {
+			t.Fatalf("%s: alpha/beta: %v", bd.FEN(), err)
+		}
+		nm := NewAlphaBeta(depth)
+		nm.Futility, nm.NullMove = futility, nullMove
+		nm.Negamax = true
+		nmPit, nmValue, err := nm.ChooseMove(context.Background(), bd, false)
+		if err != nil {
+			t.Fatalf("%s: negamax: %v", bd.FEN(), err)
+		}
+		if nmPit != abPit || nmValue != abValue {
+			t.Errorf("%s depth %d, futility %v, null move %v: negamax %d (%d), alpha/beta %d (%d)",
+				bd.FEN(), depth, futility, nullMove, nmPit, nmValue, abPit, abValue)
+		}
+	}
+}
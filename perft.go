@@ -0,0 +1,27 @@
+package kalah
+
+// Perft counts the positions exactly depth moves from bd, player
+// to move, the way chess programs check their move generators.
+// Every MakeMove counts as a move, bonus moves too. Games that
+// CheckEnd says are over before depth moves don't count, and don't
+// go any deeper. Any change to MakeMove or CheckEnd that changes
+// the rules of the game shows up as a different count.
+func Perft(bd Board, player int, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	count := 0
+	var buf [MaxPits]int
+	for _, pit := range bd.appendLegalMoves(buf[:0], player) {
+		bd2 := bd.Clone()
+		next, _, err := MakeMove(&bd2, pit, player)
+		if err != nil {
+			panic(err) // only legal moves get tried
+		}
+		if end, _ := CheckEnd(&bd2); end {
+			continue
+		}
+		count += Perft(bd2, next, depth-1)
+	}
+	return count
+}
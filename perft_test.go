@@ -0,0 +1,26 @@
+package kalah
+
+import "testing"
+
+// TestPerft checks move generation against node counts from the
+// starting position. They came from a brute force count, done
+// separately from MakeMove and CheckEnd, and agree with Perft's.
+func TestPerft(t *testing.T) {
+	tests := []struct {
+		pits, stones int
+		counts       []int // counts[d-1] is Perft to depth d
+	}{
+		{6, 2, []int{6, 35, 180, 855, 3737, 15420, 60533}},
+		{6, 4, []int{6, 35, 185, 942, 4690, 23233}},
+		{4, 3, []int{4, 15, 50, 158, 488, 1509, 4627}},
+	}
+	for _, tt := range tests {
+		bd := NewBoardPits(tt.pits, tt.stones)
+		for i, want := range tt.counts {
+			depth := i + 1
+			if got := Perft(bd, MAXIMIZER, depth); got != want {
+				t.Errorf("%d pits, %d stones, Perft depth %d = %d, want %d", tt.pits, tt.stones, depth, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package kalah
+
+// Phase is how far along a game is, for evaluations that
+// weigh things differently early and late.
+type Phase int
+
+// The phases, in the order a game goes through them.
+const (
+	Opening Phase = iota
+	Midgame
+	Endgame
+)
+
+func (ph Phase) String() string {
+	switch ph {
+	case Opening:
+		return "opening"
+	case Midgame:
+		return "midgame"
+	case Endgame:
+		return "endgame"
+	}
+	return "unknown phase"
+}
+
+// endgameStorePercent is how much of the winning number of stones
+// either store has to have for the game to be in its endgame.
+const endgameStorePercent = 40
+
+// PhaseOfGame says which phase of the game p is in. It's the opening
+// while both stores are empty. Stones only leave the pits for the
+// stores, so all of them are still in play. It's the endgame once
+// either store has more than 40% of the stones it takes to win,
+// and the midgame in between.
+func (p Board) PhaseOfGame() Phase {
+	maxstore, minstore := p.maxpits[p.pits], p.minpits[p.pits]
+	if maxstore == 0 && minstore == 0 {
+		return Opening
+	}
+	limit := endgameStorePercent * p.winningStones()
+	if 100*maxstore > limit || 100*minstore > limit {
+		return Endgame
+	}
+	return Midgame
+}
+
+// winningStones is how many stones a store needs to win:
+// more than half of them.
+func (p Board) winningStones() int {
+	return p.TotalStones()/2 + 1
+}
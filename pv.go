@@ -0,0 +1,98 @@
+package kalah
+
+// pvTable keeps track of the principal variation, the line of play
+// alpha/beta expects, both sides making the moves it thinks best.
+// Each node gets its line from the child that improved its value the
+// most: that child's move, then the child's own line. A node's height
+// is how many moves below the root it is, which ply isn't, since a
+// bonus move doesn't change the ply. Like abTree, a nil *pvTable does
+// nothing, so alpha/beta doesn't have to check for one.
+type pvTable struct {
+	lines  [][]int // lines[h], the line below the node at height h being searched
+	height int     // height of the next node entered
+	best   []int   // the root's line for the last search that finished
+}
+
+// newSearch forgets the last search's line.
+func (t *pvTable) newSearch() {
+	if t == nil {
+		return
+	}
+	t.height = 0
+	t.best = t.best[:0]
+}
+
+// enter starts a node, with no line yet, and gives its height.
+func (t *pvTable) enter() int {
+	if t == nil {
+		return 0
+	}
+	h := t.height
+	t.height++
+	for len(t.lines) <= h+1 {
+		t.lines = append(t.lines, nil)
+	}
+	t.lines[h] = t.lines[h][:0]
+	return h
+}
+
+// leave finishes the node entered last.
+func (t *pvTable) leave() {
+	if t != nil {
+		t.height--
+	}
+}
+
+// child clears the line of the node at height h's next child, which
+// might be a game over or a transposition table hit, and not get entered.
+func (t *pvTable) child(h int) {
+	if t != nil {
+		t.lines[h+1] = t.lines[h+1][:0]
+	}
+}
+
+// update makes pit, then the line of the child it led to, the node at
+// height h's line.
+func (t *pvTable) update(h, pit int) {
+	if t == nil {
+		return
+	}
+	t.lines[h] = append(append(t.lines[h][:0], pit), t.lines[h+1]...)
+}
+
+// finish keeps the root's line, at height 0, once its search is done.
+func (t *pvTable) finish() {
+	if t != nil {
+		t.best = append(t.best[:0], t.lines[0]...)
+	}
+}
+
+// only makes pit the whole line, for a move chosen without one.
+func (t *pvTable) only(pit int) {
+	if t != nil {
+		t.best = append(t.best[:0], pit)
+	}
+}
+
+// EnableBestLine turns keeping track of the principal variation on
+// or off, for BestLine. It costs a little time at every node.
+func (ab *AlphaBeta) EnableBestLine(enabled bool) {
+	ab.line = nil
+	if enabled {
+		ab.line = &pvTable{}
+	}
+}
+
+// BestLine gives the pits of the principal variation the most recent
+// search found, with EnableBestLine on: MAXIMIZER's move, the reply
+// alpha/beta expects, and so on, as far as the search went. Moves
+// alternate players, except after a bonus move. The line can stop
+// short where a transposition table had the value. MTD(f)'s
+// zero-window searches don't find a line, so with MTDF, or for an
+// opening book move, it's only the move.
+func (ab *AlphaBeta) BestLine() []int {
+	if ab.line == nil {
+		return nil
+	}
+	return append([]int(nil), ab.line.best...)
+}
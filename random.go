@@ -0,0 +1,28 @@
+package kalah
+
+import (
+	"context"
+	"math/rand"
+)
+
+// RandomPlayer picks one of MAXIMIZER's legal moves at random,
+// a baseline for other players to beat. It has its own random number
+// generator, so two RandomPlayers in the same game don't share state.
+type RandomPlayer struct {
+	rng *rand.Rand
+}
+
+// NewRandomPlayer seeds a RandomPlayer's random number generator.
+func NewRandomPlayer(seed int64) *RandomPlayer {
+	return &RandomPlayer{rng: rand.New(rand.NewSource(seed))}
+}
+
+// ChooseMove is a ChooserFunction. The value is always 0,
+// random play doesn't think one move is better than another.
+func (r *RandomPlayer) ChooseMove(ctx context.Context, bd Board, print bool) (bestpit int, value int, err error) {
+	moves := bd.LegalMoves(MAXIMIZER)
+	if moves == nil {
+		return -1, 0, ErrNoMoves
+	}
+	return moves[r.rng.Intn(len(moves))], 0, nil
+}
@@ -0,0 +1,105 @@
+package kalah
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// RolloutPolicy picks the moves of MCTS's playouts, the Simulation
+// step: one of player's legal moves on bd, which it mustn't change,
+// any random choices made with rng, the MCTS's own random numbers.
+// There's always at least one, or the game would be over.
+// String gives the policy the way ParseRollout takes it.
+type RolloutPolicy interface {
+	SelectMove(bd *Board, player int, rng *rand.Rand) int
+	String() string
+}
+
+// RandomRollout picks any legal move, all of them alike.
+// It's the quickest, and what MCTS uses unless told otherwise.
+type RandomRollout struct{}
+
+// SelectMove is part of the RolloutPolicy interface.
+func (RandomRollout) SelectMove(bd *Board, player int, rng *rand.Rand) int {
+	return bd.randomMove(player, rng)
+}
+
+func (RandomRollout) String() string { return "random" }
+
+// GreedyRollout picks the move that puts the most stones in player's
+// store right away, one of them at random if there's a tie. It makes
+// every legal move to find out, so its playouts take longer, but they
+// look more like real games.
+type GreedyRollout struct{}
+
+// SelectMove is part of the RolloutPolicy interface.
+func (GreedyRollout) SelectMove(bd *Board, player int, rng *rand.Rand) int {
+	var buf [MaxPits]int
+	best, bestGain, ties := -1, -1, 0
+	before := bd.Store(player)
+	for _, pit := range bd.appendLegalMoves(buf[:0], player) {
+		after := bd.Clone()
+		if _, _, err := MakeMove(&after, pit, player); err != nil {
+			panic(err) // appendLegalMoves only gives non-empty pits
+		}
+		gain := after.Store(player) - before
+		switch {
+		case gain > bestGain:
+			best, bestGain, ties = pit, gain, 1
+		case gain == bestGain:
+			// each of the tied moves ends up best with the same chance
+			ties++
+			if rng.Intn(ties) == 0 {
+				best = pit
+			}
+		}
+	}
+	return best
+}
+
+func (GreedyRollout) String() string { return "greedy" }
+
+// MixedRollout plays like GreedyRollout with probability P,
+// and like RandomRollout otherwise.
+type MixedRollout struct {
+	P float64
+}
+
+// SelectMove is part of the RolloutPolicy interface.
+func (m MixedRollout) SelectMove(bd *Board, player int, rng *rand.Rand) int {
+	if rng.Float64() < m.P {
+		return GreedyRollout{}.SelectMove(bd, player, rng)
+	}
+	return bd.randomMove(player, rng)
+}
+
+func (m MixedRollout) String() string {
+	return "mixed:" + strconv.FormatFloat(m.P, 'g', -1, 64)
+}
+
+// ParseRollout turns "random", "greedy" or "mixed:P", like
+// "mixed:0.5", into a RolloutPolicy. Plain "mixed" is "mixed:0.5".
+func ParseRollout(str string) (RolloutPolicy, error) {
+	name, arg := str, ""
+	if i := strings.Index(str, ":"); i >= 0 {
+		name, arg = str[:i], str[i+1:]
+	}
+	switch {
+	case name == "random" && arg == "":
+		return RandomRollout{}, nil
+	case name == "greedy" && arg == "":
+		return GreedyRollout{}, nil
+	case name == "mixed":
+		p := 0.5
+		if arg != "" {
+			var err error
+			if p, err = strconv.ParseFloat(arg, 64); err != nil || p < 0 || p > 1 {
+				return nil, fmt.Errorf("rollout %q: want a probability 0 to 1, not %q", str, arg)
+			}
+		}
+		return MixedRollout{P: p}, nil
+	}
+	return nil, fmt.Errorf("unknown rollout %q, random, greedy or mixed:P", str)
+}
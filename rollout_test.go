@@ -0,0 +1,26 @@
+package kalah
+
+import (
+	"context"
+	"testing"
+)
+
+// BenchmarkRollout times a 10,000 iteration MCTS search for a move on
+// a new board with each rollout policy, what the better playouts of
+// the greedy ones cost.
+func BenchmarkRollout(b *testing.B) {
+	const iterations = 10000
+	bd := NewBoard(4)
+	for _, rollout := range []RolloutPolicy{RandomRollout{}, GreedyRollout{}, MixedRollout{P: 0.5}} {
+		b.Run(rollout.String(), func(b *testing.B) {
+			p := NewMCTS(iterations, 1.414)
+			p.Seed(807)
+			p.Rollout = rollout
+			for i := 0; i < b.N; i++ {
+				if _, _, err := p.ChooseMove(context.Background(), bd, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
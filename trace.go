@@ -0,0 +1,99 @@
+package kalah
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// abTrace writes the moves alpha/beta searches as indented text, one
+// line per move: the ply it got made at, who made it, the pit, the
+// value the search found for it, and the alpha, beta window of the
+// node it got made from, as the move got tried, from MAXIMIZER's point
+// of view even for negamax. Moves deeper than maxDepth below the root
+// don't get written. A move's value isn't known until its children are
+// done, so the lines for a root move and everything below it wait
+// until the root move's value is in, then get written in the order
+// the moves got made, each move above its children.
+// Like abTree, a nil *abTrace does nothing.
+type abTrace struct {
+	w        io.Writer
+	maxDepth int
+	depth    int         // moves below the root of the move being searched
+	lines    []traceLine // the current root move's, and below it
+	open     []int       // indexes in lines of moves entered, but not left
+}
+
+// traceLine is one move abTrace writes.
+type traceLine struct {
+	depth, ply, player, pit int
+	value, alpha, beta      int
+}
+
+// reset starts a search of the root position to maxPly, window
+// alpha, beta, with a line saying so.
+func (t *abTrace) reset(maxPly, alpha, beta int) {
+	if t == nil {
+		return
+	}
+	t.depth = 0
+	t.lines = t.lines[:0]
+	t.open = t.open[:0]
+	fmt.Fprintf(t.w, "alpha/beta search to ply %d, alpha=%d beta=%d\n", maxPly, alpha, beta)
+}
+
+// enter gets called just before player makes pit's move at ply, from
+// a node with the window alpha, beta.
+func (t *abTrace) enter(player, pit, ply, alpha, beta int) {
+	if t == nil {
+		return
+	}
+	t.depth++
+	if t.depth > t.maxDepth {
+		return
+	}
+	t.open = append(t.open, len(t.lines))
+	t.lines = append(t.lines, traceLine{depth: t.depth, ply: ply, player: player, pit: pit, alpha: alpha, beta: beta})
+}
+
+// leave gets called with the value of the move the matching enter
+// call made.
+func (t *abTrace) leave(value int) {
+	if t == nil {
+		return
+	}
+	t.depth--
+	if t.depth >= t.maxDepth {
+		return
+	}
+	t.lines[t.open[len(t.open)-1]].value = value
+	t.open = t.open[:len(t.open)-1]
+	if t.depth == 0 {
+		t.flush()
+	}
+}
+
+// flush writes the lines of a root move whose value is in.
+func (t *abTrace) flush() {
+	for _, l := range t.lines {
+		who := "MAX"
+		if l.player == MINIMIZER {
+			who = "MIN"
+		}
+		fmt.Fprintf(t.w, "%s[ply=%d player=%s pit=%d value=%d alpha=%d beta=%d]\n",
+			strings.Repeat("  ", l.depth-1), l.ply, who, l.pit, l.value, l.alpha, l.beta)
+	}
+	t.lines = t.lines[:0]
+}
+
+// TraceSearch has every search write the moves it tries, down to
+// maxDepth moves below the root, to w, as indented text, each with its
+// value and the alpha, beta window when it got tried. A maxDepth
+// of 0 or less, or a nil w, turns it back off. It doesn't trace MTD(f),
+// or opening book moves, which don't get searched.
+func (ab *AlphaBeta) TraceSearch(w io.Writer, maxDepth int) {
+	ab.trace = nil
+	if w != nil && maxDepth > 0 {
+		ab.trace = &abTrace{w: w, maxDepth: maxDepth}
+	}
+}
@@ -0,0 +1,137 @@
+package kalah
+
+import "math/bits"
+
+// DefaultTTBuckets is how many buckets NewTranspositionTable(0) makes,
+// 2 entries of 16 bytes each, 8 megabytes in all.
+const DefaultTTBuckets = 1 << 18
+
+// ttMinDepth is the fewest plies to the horizon a position has to have
+// for alpha/beta to look it up. Hashing a board isn't free, and nearer
+// the horizon it costs about as much as the search it saves.
+const ttMinDepth = 2
+
+// ttBound says what a transposition table entry's value is:
+// the value, or a bound on it, from a search that failed low or high.
+type ttBound uint8
+
+const (
+	ttEmpty ttBound = iota // nothing stored
+	ttExact                // the value, searched with a window it fell inside
+	ttLower                // failed high: the value is at least this
+	ttUpper                // failed low: the value is at most this
+)
+
+// ttEntry is one position's search result. depth is how many plies
+// there were left to the horizon below it.
+type ttEntry struct {
+	key   uint64
+	value int32
+	depth int16
+	bound ttBound
+	gen   uint8 // TranspositionTable.gen when it got stored
+}
+
+// TranspositionTable remembers values that alpha/beta already worked
+// out, keyed by Board.Hash with the player to move, so a position
+// reached again by a different order of moves, or by the next depth of
+// iterative deepening or MTD(f)'s next zero-window search, doesn't
+// have to be searched again. Each bucket has two entries, the usual
+// "two-deep" replacement scheme: slot 0 keeps the deepest search,
+// only getting replaced by one at least as deep, or by anything once
+// it's from an earlier move's search; slot 1 takes whatever doesn't
+// go in slot 0. A deep result is worth more, it saves searching a
+// bigger tree, but shallow ones near the horizon come up again soon.
+type TranspositionTable struct {
+	buckets [][2]ttEntry
+	mask    uint64
+	gen     uint8
+
+	// Probes, Hits and Stores count lookups, lookups that ended
+	// the search of a position, and entries stored, since the
+	// table got made.
+	Probes, Hits, Stores int64
+}
+
+// NewTranspositionTable makes a table of buckets buckets, rounded up
+// to a power of 2, or DefaultTTBuckets if buckets is 0 or less.
+func NewTranspositionTable(buckets int) *TranspositionTable {
+	if buckets <= 0 {
+		buckets = DefaultTTBuckets
+	}
+	size := 1 << bits.Len(uint(buckets-1))
+	return &TranspositionTable{
+		buckets: make([][2]ttEntry, size),
+		mask:    uint64(size - 1),
+	}
+}
+
+// Clear empties t.
+func (t *TranspositionTable) Clear() {
+	for i := range t.buckets {
+		t.buckets[i] = [2]ttEntry{}
+	}
+}
+
+// newSearch marks entries already in t as old, so that a new search's
+// entries can replace them in slot 0, whatever their depth. They still
+// get looked up: a position's value doesn't change from move to move.
+func (t *TranspositionTable) newSearch() {
+	t.gen++
+}
+
+// probe looks for key, with depth or more plies left to the horizon,
+// whose value settles a search at ply with window alpha, beta: it's
+// exact, or a bound outside the window.
+func (t *TranspositionTable) probe(key uint64, depth, ply, alpha, beta int) (int, bool) {
+	t.Probes++
+	b := &t.buckets[key&t.mask]
+	for i := range b {
+		e := &b[i]
+		if e.bound == ttEmpty || e.key != key || int(e.depth) < depth {
+			continue
+		}
+		value := fromTT(int(e.value), ply)
+		if e.bound == ttExact || (e.bound == ttLower && value >= beta) || (e.bound == ttUpper && value <= alpha) {
+			t.Hits++
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+// store saves value, which a search at ply with depth plies to go and
+// window alpha, beta found for key.
+func (t *TranspositionTable) store(key uint64, depth, ply, value, alpha, beta int) {
+	t.Stores++
+	e := ttEntry{key: key, value: int32(toTT(value, ply)), depth: int16(depth), bound: ttExact, gen: t.gen}
+	switch {
+	case value <= alpha:
+		e.bound = ttUpper
+	case value >= beta:
+		e.bound = ttLower
+	}
+	b := &t.buckets[key&t.mask]
+	if b[0].bound == ttEmpty || b[0].gen != t.gen || depth >= int(b[0].depth) {
+		b[0] = e
+		return
+	}
+	b[1] = e
+}
+
+// toTT takes the ply out of a value found at ply. Values count plies
+// from the root, win and static values down, losses up, but a position
+// can turn up at any ply. fromTT puts ply back in.
+func toTT(value, ply int) int {
+	if value <= LOSS/2 {
+		return value - ply
+	}
+	return value + ply
+}
+
+func fromTT(value, ply int) int {
+	if value <= LOSS/2 {
+		return value + ply
+	}
+	return value - ply
+}
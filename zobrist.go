@@ -0,0 +1,75 @@
+package kalah
+
+import "math/rand"
+
+// DefaultZobristSeed generates the Zobrist key table unless
+// SetZobristSeed says otherwise. Keep it fixed, so hashes are the
+// same from one run to the next.
+const DefaultZobristSeed = 20130317
+
+// zobristMaxStones is how many different stone counts per pit get
+// their own key. Counts past it wrap around, which only matters for
+// pits or stores holding more than 255 stones. Indexing with a uint8
+// wraps for free, and spares Hash() any bounds checks.
+const zobristMaxStones = 256
+
+// zobristKeys has a random key for every side, pit (including stores)
+// and stone count, and one for each player who could have made
+// the last move.
+type zobristKeys struct {
+	pits   [2][MaxPits + 1][zobristMaxStones]uint64
+	player [2]uint64
+}
+
+var zobrist = newZobristKeys(DefaultZobristSeed)
+
+func newZobristKeys(seed int64) *zobristKeys {
+	r := rand.New(rand.NewSource(seed))
+	z := &zobristKeys{}
+	for side := range z.pits {
+		for pit := range z.pits[side] {
+			for count := range z.pits[side][pit] {
+				z.pits[side][pit][count] = r.Uint64()
+			}
+		}
+	}
+	z.player[0] = r.Uint64()
+	z.player[1] = r.Uint64()
+	return z
+}
+
+// SetZobristSeed regenerates the Zobrist key table from seed.
+// Call it before any hashing, hashes from different seeds
+// don't compare.
+func SetZobristSeed(seed int64) {
+	zobrist = newZobristKeys(seed)
+}
+
+// Hash gives a Zobrist hash of the stones on the board and the player
+// who made the last move. Like Equal, display orientation doesn't count.
+// Pointer receiver, so alphaBeta can call it without copying the board.
+func (p *Board) Hash() uint64 {
+	z := zobrist
+	var h uint64
+	for i := 0; i <= p.pits; i++ {
+		h ^= z.pits[0][i][uint8(p.maxpits[i])]
+		h ^= z.pits[1][i][uint8(p.minpits[i])]
+	}
+	switch p.player {
+	case MAXIMIZER:
+		h ^= z.player[0]
+	case MINIMIZER:
+		h ^= z.player[1]
+	}
+	return h
+}
+
+// hashToMove is Hash for p with next to move. After a bonus move,
+// that's the player who made the last move, so it's not Hash.
+func (p *Board) hashToMove(next int) uint64 {
+	saved := p.player
+	p.player = -next
+	h := p.Hash()
+	p.player = saved
+	return h
+}
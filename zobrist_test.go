@@ -0,0 +1,27 @@
+package kalah
+
+import (
+	"fmt"
+	"testing"
+)
+
+// hashSink keeps the compiler from optimizing away the hashing
+// that BenchmarkHash times.
+var hashSink uint64
+
+// BenchmarkHash times Board.Hash, which alphaBeta calls at every node
+// with a transposition table, on boards with 4, 6 and 8 pits a side.
+// It ought to take under 20ns.
+func BenchmarkHash(b *testing.B) {
+	for _, pits := range []int{4, 6, MaxPits} {
+		bd := NewBoardPits(pits, 4)
+		if _, _, err := MakeMove(&bd, 1, MAXIMIZER); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("%d pits", pits), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				hashSink ^= bd.Hash()
+			}
+		})
+	}
+}